@@ -1,9 +1,15 @@
 package config
 
 import (
+	"errors"
+	"io/fs"
+	"time"
+
 	"github.com/spf13/viper"
 )
 
+const defaultTrashRetentionDays = 30
+
 type Config struct {
 	PORT                    string `mapstructure:"PORT"`
 	DbString                string `mapstructure:"DB_STRING"`
@@ -20,6 +26,16 @@ type Config struct {
 	AxiomOrg                string `mapstructure:"AXIOM_ORG"`
 	AxiomDataset            string `mapstructure:"AXIOM_DATASET"`
 	EncSecret               string `mapstructure:"ENC_SECRET"`
+	TrashRetentionDays      int    `mapstructure:"TRASH_RETENTION_DAYS"`
+}
+
+// TrashRetention is how long deleted cards, lists and tags stay restorable.
+func (c Config) TrashRetention() time.Duration {
+	days := c.TrashRetentionDays
+	if days <= 0 {
+		days = defaultTrashRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -29,8 +45,9 @@ func LoadConfig(path string) (config Config, err error) {
 	viper.SetConfigFile(".env")
 	viper.AutomaticEnv()
 
+	// Without a .env file the settings come from the environment alone.
 	err = viper.ReadInConfig()
-	if err != nil {
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
 		return
 	}
 
@@ -2,22 +2,121 @@ package db
 
 import (
 	"github.com/Cognize-AI/client-cognize/config"
+	"github.com/Cognize-AI/client-cognize/internal/fieldtype"
+	"github.com/Cognize-AI/client-cognize/logger"
 	"github.com/Cognize-AI/client-cognize/models"
+	"go.uber.org/zap"
 )
 
+// searchIndexes back the full-text queries in internal/search. The indexed
+// expressions must stay identical to the ones used there.
+var searchIndexes = []string{
+	"CREATE INDEX IF NOT EXISTS idx_cards_name_fts ON cards USING GIN (to_tsvector('simple', coalesce(name, '')))",
+	"CREATE INDEX IF NOT EXISTS idx_cards_email_fts ON cards USING GIN (to_tsvector('simple', coalesce(email, '')))",
+	"CREATE INDEX IF NOT EXISTS idx_cards_company_name_fts ON cards USING GIN (to_tsvector('simple', coalesce(company_name, '')))",
+	"CREATE INDEX IF NOT EXISTS idx_cards_designation_fts ON cards USING GIN (to_tsvector('simple', coalesce(designation, '')))",
+	"CREATE INDEX IF NOT EXISTS idx_cards_ai_summary_fts ON cards USING GIN (to_tsvector('simple', coalesce(ai_summary, '')))",
+	"CREATE INDEX IF NOT EXISTS idx_activities_content_fts ON activities USING GIN (to_tsvector('simple', coalesce(content, '')))",
+	"CREATE INDEX IF NOT EXISTS idx_field_values_value_fts ON field_values USING GIN (to_tsvector('simple', coalesce(value, '')))",
+	"CREATE INDEX IF NOT EXISTS idx_tags_name_fts ON tags USING GIN (to_tsvector('simple', coalesce(name, '')))",
+}
+
+// migrateDefaultBoards gives every user a default board and moves lists that
+// predate boards onto it.
+func migrateDefaultBoards() error {
+	if err := config.DB.Exec(`
+		INSERT INTO boards (name, user_id, is_default, created_at, updated_at)
+		SELECT ?, u.id, true, now(), now()
+		FROM users u
+		WHERE u.deleted_at IS NULL AND NOT EXISTS (
+			SELECT 1 FROM boards b WHERE b.user_id = u.id AND b.is_default AND b.deleted_at IS NULL
+		)`, models.DefaultBoardName).Error; err != nil {
+		return err
+	}
+	return config.DB.Exec(`
+		UPDATE lists SET board_id = b.id
+		FROM boards b
+		WHERE b.user_id = lists.user_id AND b.is_default AND b.deleted_at IS NULL
+		  AND (lists.board_id IS NULL OR lists.board_id = 0)`).Error
+}
+
+// uniqueDefaultBoards keeps the oldest default board of each user as the
+// default and adds the unique index board.DefaultBoard relies on.
+func uniqueDefaultBoards() error {
+	if err := config.DB.Exec(`
+		UPDATE boards SET is_default = false
+		WHERE is_default AND deleted_at IS NULL AND EXISTS (
+			SELECT 1 FROM boards b
+			WHERE b.user_id = boards.user_id AND b.is_default AND b.deleted_at IS NULL AND b.id < boards.id
+		)`).Error; err != nil {
+		return err
+	}
+	return config.DB.Exec(`
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_boards_user_default ON boards (user_id)
+		WHERE is_default AND deleted_at IS NULL`).Error
+}
+
+// backfillStageTransitions records a creation transition for cards that have
+// none, i.e. cards created before transitions were tracked. Their earlier
+// moves are unknown, so they count as created in their current list. Cards in
+// the trash are left out like everywhere else in the analytics; a restored
+// card is picked up on the next start.
+func backfillStageTransitions() error {
+	return config.DB.Exec(`
+		INSERT INTO stage_transitions (card_id, from_list_id, to_list_id, user_id, created_at, updated_at)
+		SELECT c.id, 0, c.list_id, l.user_id, c.created_at, now()
+		FROM cards c
+		JOIN lists l ON l.id = c.list_id
+		WHERE c.deleted_at IS NULL
+		  AND NOT EXISTS (SELECT 1 FROM stage_transitions st WHERE st.card_id = c.id)`).Error
+}
+
 func SyncDB() {
 	config.DB.Exec("DROP INDEX IF EXISTS idx_activities_card_id;\n")
 	err := config.DB.AutoMigrate(
 		models.User{},
+		models.Board{},
 		models.List{},
+		models.ListRequirement{},
 		models.Card{},
 		models.Tag{},
 		models.Key{},
 		models.Activity{},
 		models.FieldDefinition{},
 		models.FieldValue{},
+		models.FieldOption{},
+		models.FieldValueOption{},
+		models.Import{},
+		models.CardHistory{},
+		models.StageTransition{},
+		models.AutomationRule{},
+		models.AutomationRun{},
+		models.SavedView{},
+		models.PipelineTemplate{},
 	)
 	if err != nil {
 		return
 	}
+
+	if err := migrateDefaultBoards(); err != nil {
+		logger.Logger.Error("failed to migrate lists to default boards", zap.Error(err))
+	}
+
+	if err := uniqueDefaultBoards(); err != nil {
+		logger.Logger.Error("failed to add default board index", zap.Error(err))
+	}
+
+	if err := backfillStageTransitions(); err != nil {
+		logger.Logger.Error("failed to backfill stage transitions", zap.Error(err))
+	}
+
+	if err := fieldtype.LinkMissing(config.DB); err != nil {
+		logger.Logger.Error("failed to link field values to options", zap.Error(err))
+	}
+
+	for _, stmt := range searchIndexes {
+		if err := config.DB.Exec(stmt).Error; err != nil {
+			logger.Logger.Error("failed to create search index", zap.String("stmt", stmt), zap.Error(err))
+		}
+	}
 }
@@ -0,0 +1,46 @@
+package analytics
+
+import (
+	"context"
+	"time"
+
+	"github.com/Cognize-AI/client-cognize/models"
+)
+
+type StageAnalyticsReq struct {
+	// BoardID selects the board, the user's default board when zero.
+	BoardID uint `form:"board_id"`
+	// From and To bound the range as YYYY-MM-DD (To inclusive) or RFC 3339
+	// (To exclusive). The default is the last 30 days.
+	From string `form:"from"`
+	To   string `form:"to"`
+}
+
+type StageStats struct {
+	ListID uint   `json:"list_id"`
+	Name   string `json:"name"`
+	// Entered and Exited count distinct cards entering or leaving the stage
+	// within the range.
+	Entered int64 `json:"entered"`
+	Exited  int64 `json:"exited"`
+	// Advanced counts cards that entered in range and later reached a stage
+	// further along the board.
+	Advanced       int64   `json:"advanced"`
+	ConversionRate float64 `json:"conversion_rate"`
+	// Time in stage, over stints that ended within the range.
+	CompletedStints int64    `json:"completed_stints"`
+	MedianSeconds   *float64 `json:"median_seconds"`
+	P90Seconds      *float64 `json:"p90_seconds"`
+}
+
+type StageAnalyticsRes struct {
+	BoardID      uint         `json:"board_id"`
+	From         time.Time    `json:"from"`
+	To           time.Time    `json:"to"`
+	CardsCreated int64        `json:"cards_created"`
+	Stages       []StageStats `json:"stages"`
+}
+
+type Service interface {
+	GetStageAnalytics(ctx context.Context, req StageAnalyticsReq, user models.User) (*StageAnalyticsRes, error)
+}
@@ -0,0 +1,40 @@
+package analytics
+
+import (
+	"net/http"
+
+	"github.com/Cognize-AI/client-cognize/logger"
+	"github.com/Cognize-AI/client-cognize/util"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{s}
+}
+
+func (h *Handler) GetStageAnalytics(c *gin.Context) {
+	currentUser, valid := util.GetCurrentUser(c)
+	if !valid {
+		return
+	}
+
+	var req StageAnalyticsReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.Service.GetStageAnalytics(c, req, currentUser)
+	if err != nil {
+		logger.Logger.Error("Error getting stage analytics :", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
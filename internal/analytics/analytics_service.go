@@ -0,0 +1,217 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Cognize-AI/client-cognize/config"
+	"github.com/Cognize-AI/client-cognize/internal/board"
+	"github.com/Cognize-AI/client-cognize/logger"
+	"github.com/Cognize-AI/client-cognize/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const defaultRange = 30 * 24 * time.Hour
+
+type service struct {
+	timeout time.Duration
+	DB      *gorm.DB
+}
+
+func NewService() Service {
+	return &service{
+		time.Duration(20) * time.Second,
+		config.DB,
+	}
+}
+
+// RecordTransitions stores stage transitions. Pass the transaction that
+// changes the cards' lists.
+func RecordTransitions(db *gorm.DB, transitions ...models.StageTransition) error {
+	if len(transitions) == 0 {
+		return nil
+	}
+	return db.Create(&transitions).Error
+}
+
+// Transition builds a transition of a card from one list to another. Use 0 as
+// fromListID when the card is created.
+func Transition(cardID, fromListID, toListID, userID uint) models.StageTransition {
+	return models.StageTransition{
+		CardID:     cardID,
+		FromListID: fromListID,
+		ToListID:   toListID,
+		UserID:     userID,
+	}
+}
+
+// parseBound parses a date or timestamp. A bare date used as the upper bound
+// covers the whole day.
+func parseBound(v string, upper bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD or RFC 3339", v)
+	}
+	if upper {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+type stageCount struct {
+	ListID   uint
+	Entered  int64
+	Advanced int64
+}
+
+type stageExit struct {
+	ListID uint
+	Exited int64
+}
+
+type stageDuration struct {
+	ListID          uint
+	CompletedStints int64
+	MedianSeconds   *float64
+	P90Seconds      *float64
+}
+
+func (s *service) GetStageAnalytics(ctx context.Context, req StageAnalyticsReq, user models.User) (*StageAnalyticsRes, error) {
+	to := time.Now()
+	if req.To != "" {
+		t, err := parseBound(req.To, true)
+		if err != nil {
+			return nil, err
+		}
+		to = t
+	}
+	from := to.Add(-defaultRange)
+	if req.From != "" {
+		t, err := parseBound(req.From, false)
+		if err != nil {
+			return nil, err
+		}
+		from = t
+	}
+	if !from.Before(to) {
+		return nil, errors.New("from must be before to")
+	}
+
+	_board, err := board.FindBoard(s.DB, req.BoardID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var lists []models.List
+	if err := s.DB.Where("board_id = ?", _board.ID).Order("list_order ASC, id ASC").Find(&lists).Error; err != nil {
+		return nil, err
+	}
+	res := &StageAnalyticsRes{BoardID: _board.ID, From: from, To: to, Stages: []StageStats{}}
+	if len(lists) == 0 {
+		return res, nil
+	}
+	listIDs := make([]uint, len(lists))
+	for i, l := range lists {
+		listIDs[i] = l.ID
+	}
+
+	// Conversion: cards entering a stage in range that later entered a stage
+	// with a higher list order on the same board.
+	var counts []stageCount
+	if err := s.DB.Raw(`
+		SELECT e.to_list_id AS list_id,
+		       COUNT(DISTINCT e.card_id) AS entered,
+		       COUNT(DISTINCT e.card_id) FILTER (WHERE EXISTS (
+		           SELECT 1 FROM stage_transitions later
+		           JOIN lists ll ON ll.id = later.to_list_id
+		           WHERE later.card_id = e.card_id AND later.deleted_at IS NULL
+		             AND later.created_at > e.created_at
+		             AND ll.board_id = l.board_id AND ll.list_order > l.list_order
+		       )) AS advanced
+		FROM stage_transitions e
+		JOIN lists l ON l.id = e.to_list_id
+		WHERE e.deleted_at IS NULL AND e.to_list_id IN ? AND e.created_at >= ? AND e.created_at < ?
+		GROUP BY e.to_list_id`, listIDs, from, to).
+		Scan(&counts).Error; err != nil {
+		logger.Logger.Error("failed to compute stage conversion", zap.Error(err))
+		return nil, err
+	}
+
+	var exits []stageExit
+	if err := s.DB.Raw(`
+		SELECT from_list_id AS list_id, COUNT(DISTINCT card_id) AS exited
+		FROM stage_transitions
+		WHERE deleted_at IS NULL AND from_list_id IN ? AND created_at >= ? AND created_at < ?
+		GROUP BY from_list_id`, listIDs, from, to).
+		Scan(&exits).Error; err != nil {
+		logger.Logger.Error("failed to compute stage exits", zap.Error(err))
+		return nil, err
+	}
+
+	// A stint runs from a card entering a list until its next transition.
+	var durations []stageDuration
+	if err := s.DB.Raw(`
+		WITH stints AS (
+			SELECT to_list_id AS list_id, created_at AS entered_at,
+			       LEAD(created_at) OVER (PARTITION BY card_id ORDER BY created_at, id) AS exited_at
+			FROM stage_transitions
+			WHERE deleted_at IS NULL AND user_id = ?
+		)
+		SELECT list_id,
+		       COUNT(*) AS completed_stints,
+		       percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM exited_at - entered_at)) AS median_seconds,
+		       percentile_cont(0.9) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM exited_at - entered_at)) AS p90_seconds
+		FROM stints
+		WHERE list_id IN ? AND exited_at >= ? AND exited_at < ?
+		GROUP BY list_id`, user.ID, listIDs, from, to).
+		Scan(&durations).Error; err != nil {
+		logger.Logger.Error("failed to compute time in stage", zap.Error(err))
+		return nil, err
+	}
+
+	if err := s.DB.Model(&models.StageTransition{}).
+		Where("from_list_id = 0 AND to_list_id IN ? AND created_at >= ? AND created_at < ?", listIDs, from, to).
+		Count(&res.CardsCreated).Error; err != nil {
+		return nil, err
+	}
+
+	countByList := map[uint]stageCount{}
+	for _, c := range counts {
+		countByList[c.ListID] = c
+	}
+	exitByList := map[uint]int64{}
+	for _, e := range exits {
+		exitByList[e.ListID] = e.Exited
+	}
+	durationByList := map[uint]stageDuration{}
+	for _, d := range durations {
+		durationByList[d.ListID] = d
+	}
+
+	for _, l := range lists {
+		c := countByList[l.ID]
+		d := durationByList[l.ID]
+		stats := StageStats{
+			ListID:          l.ID,
+			Name:            l.Name,
+			Entered:         c.Entered,
+			Exited:          exitByList[l.ID],
+			Advanced:        c.Advanced,
+			CompletedStints: d.CompletedStints,
+			MedianSeconds:   d.MedianSeconds,
+			P90Seconds:      d.P90Seconds,
+		}
+		if c.Entered > 0 {
+			stats.ConversionRate = float64(c.Advanced) / float64(c.Entered)
+		}
+		res.Stages = append(res.Stages, stats)
+	}
+
+	return res, nil
+}
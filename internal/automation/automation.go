@@ -0,0 +1,84 @@
+package automation
+
+import (
+	"context"
+	"time"
+
+	"github.com/Cognize-AI/client-cognize/models"
+)
+
+type RuleReq struct {
+	Name string `json:"name" binding:"required"`
+	// Enabled defaults to true.
+	Enabled *bool  `json:"enabled"`
+	Trigger string `json:"trigger" binding:"required"`
+	// TriggerListID limits CARD_MOVED to cards entering the list and
+	// NO_ACTIVITY to cards in the list.
+	TriggerListID uint `json:"trigger_list_id"`
+	// TriggerTagID limits TAG_ADDED to one tag.
+	TriggerTagID uint `json:"trigger_tag_id"`
+	// TriggerFieldID limits FIELD_CHANGED to one custom field.
+	TriggerFieldID uint `json:"trigger_field_id"`
+	// InactiveDays is required for NO_ACTIVITY.
+	InactiveDays int                          `json:"inactive_days"`
+	Conditions   []models.AutomationCondition `json:"conditions"`
+	Actions      []models.AutomationAction    `json:"actions" binding:"required"`
+}
+
+type UpdateRuleReq struct {
+	ID uint `uri:"id" binding:"required"`
+	RuleReq
+}
+
+type RuleIDReq struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+type Rule struct {
+	ID             uint                         `json:"id"`
+	Name           string                       `json:"name"`
+	Enabled        bool                         `json:"enabled"`
+	Trigger        string                       `json:"trigger"`
+	TriggerListID  uint                         `json:"trigger_list_id,omitempty"`
+	TriggerTagID   uint                         `json:"trigger_tag_id,omitempty"`
+	TriggerFieldID uint                         `json:"trigger_field_id,omitempty"`
+	InactiveDays   int                          `json:"inactive_days,omitempty"`
+	Conditions     []models.AutomationCondition `json:"conditions"`
+	Actions        []models.AutomationAction    `json:"actions"`
+	CreatedAt      time.Time                    `json:"created_at"`
+	UpdatedAt      time.Time                    `json:"updated_at"`
+}
+
+type GetRulesRes struct {
+	Rules []Rule `json:"rules"`
+}
+
+type GetRunsReq struct {
+	ID     uint `uri:"id" binding:"required"`
+	Before uint `form:"before"`
+	Limit  int  `form:"limit"`
+}
+
+type Run struct {
+	ID        uint      `json:"id"`
+	CardID    uint      `json:"card_id"`
+	Trigger   string    `json:"trigger"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type GetRunsRes struct {
+	RuleID uint  `json:"rule_id"`
+	Runs   []Run `json:"runs"`
+	// NextBefore is passed as ?before= to fetch older runs, 0 when done.
+	NextBefore uint `json:"next_before"`
+}
+
+type Service interface {
+	CreateRule(ctx context.Context, req RuleReq, user models.User) (*Rule, error)
+	GetRules(ctx context.Context, user models.User) (*GetRulesRes, error)
+	UpdateRule(ctx context.Context, req UpdateRuleReq, user models.User) (*Rule, error)
+	DeleteRule(ctx context.Context, req RuleIDReq, user models.User) error
+	GetRuns(ctx context.Context, req GetRunsReq, user models.User) (*GetRunsRes, error)
+}
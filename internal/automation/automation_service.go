@@ -0,0 +1,358 @@
+package automation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Cognize-AI/client-cognize/config"
+	"github.com/Cognize-AI/client-cognize/internal/fieldtype"
+	"github.com/Cognize-AI/client-cognize/logger"
+	"github.com/Cognize-AI/client-cognize/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	maxConditions = 20
+	maxActions    = 20
+	defaultLimit  = 50
+	maxLimit      = 200
+)
+
+var triggers = map[string]bool{
+	string(models.TriggerCardCreated):  true,
+	string(models.TriggerCardMoved):    true,
+	string(models.TriggerTagAdded):     true,
+	string(models.TriggerFieldChanged): true,
+	string(models.TriggerNoActivity):   true,
+}
+
+var fieldOperators = map[string]bool{
+	"equals":     true,
+	"not_equals": true,
+	"contains":   true,
+	"empty":      true,
+	"not_empty":  true,
+}
+
+var tagOperators = map[string]bool{
+	"has":     true,
+	"not_has": true,
+}
+
+type service struct {
+	timeout time.Duration
+	DB      *gorm.DB
+}
+
+func NewService() Service {
+	return &service{
+		time.Duration(20) * time.Second,
+		config.DB,
+	}
+}
+
+// owned reports whether every id is a row of model belonging to the user.
+func owned(db *gorm.DB, model interface{}, ids []uint, userID uint) (bool, error) {
+	unique := map[uint]bool{}
+	for _, id := range ids {
+		unique[id] = true
+	}
+	if len(unique) == 0 {
+		return true, nil
+	}
+
+	var count int64
+	if err := db.Model(model).
+		Where("id IN ? AND user_id = ?", ids, userID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count == int64(len(unique)), nil
+}
+
+// validateRule checks the rule and normalizes the values that SET_FIELD
+// actions write to custom fields.
+func validateRule(db *gorm.DB, req *RuleReq, user models.User) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return errors.New("name is required")
+	}
+	if !triggers[req.Trigger] {
+		return fmt.Errorf("unknown trigger %q", req.Trigger)
+	}
+	if req.Trigger == string(models.TriggerNoActivity) && req.InactiveDays <= 0 {
+		return errors.New("inactive_days must be positive for NO_ACTIVITY")
+	}
+	if len(req.Conditions) > maxConditions {
+		return fmt.Errorf("a rule can have at most %d conditions", maxConditions)
+	}
+	if len(req.Actions) == 0 {
+		return errors.New("a rule needs at least one action")
+	}
+	if len(req.Actions) > maxActions {
+		return fmt.Errorf("a rule can have at most %d actions", maxActions)
+	}
+
+	var listIDs, tagIDs, fieldIDs []uint
+	if req.TriggerListID != 0 {
+		listIDs = append(listIDs, req.TriggerListID)
+	}
+	if req.TriggerTagID != 0 {
+		tagIDs = append(tagIDs, req.TriggerTagID)
+	}
+	if req.TriggerFieldID != 0 {
+		fieldIDs = append(fieldIDs, req.TriggerFieldID)
+	}
+
+	for i, cond := range req.Conditions {
+		set := 0
+		for _, ok := range []bool{cond.Field != "", cond.FieldID != 0, cond.TagID != 0} {
+			if ok {
+				set++
+			}
+		}
+		if set != 1 {
+			return fmt.Errorf("condition %d: set exactly one of field, field_id or tag_id", i+1)
+		}
+		switch {
+		case cond.TagID != 0:
+			if !tagOperators[cond.Operator] {
+				return fmt.Errorf("condition %d: tag conditions use has or not_has", i+1)
+			}
+			tagIDs = append(tagIDs, cond.TagID)
+		case cond.FieldID != 0:
+			if !fieldOperators[cond.Operator] {
+				return fmt.Errorf("condition %d: unknown operator %q", i+1, cond.Operator)
+			}
+			fieldIDs = append(fieldIDs, cond.FieldID)
+		default:
+			if _, ok := models.CardFields[cond.Field]; !ok {
+				return fmt.Errorf("condition %d: unknown field %q", i+1, cond.Field)
+			}
+			if !fieldOperators[cond.Operator] {
+				return fmt.Errorf("condition %d: unknown operator %q", i+1, cond.Operator)
+			}
+		}
+	}
+
+	for i, action := range req.Actions {
+		switch models.AutomationActionType(action.Type) {
+		case models.ActionAddTag, models.ActionRemoveTag:
+			if action.TagID == 0 {
+				return fmt.Errorf("action %d: tag_id is required", i+1)
+			}
+			tagIDs = append(tagIDs, action.TagID)
+		case models.ActionMoveToList:
+			if action.ListID == 0 {
+				return fmt.Errorf("action %d: list_id is required", i+1)
+			}
+			listIDs = append(listIDs, action.ListID)
+		case models.ActionCreateActivity:
+			if strings.TrimSpace(action.Value) == "" {
+				return fmt.Errorf("action %d: value is required", i+1)
+			}
+		case models.ActionSetField:
+			if (action.Field == "") == (action.FieldID == 0) {
+				return fmt.Errorf("action %d: set exactly one of field or field_id", i+1)
+			}
+			if action.FieldID != 0 {
+				fieldIDs = append(fieldIDs, action.FieldID)
+			} else if _, ok := models.CardFields[action.Field]; !ok {
+				return fmt.Errorf("action %d: unknown field %q", i+1, action.Field)
+			}
+		case models.ActionWebhook:
+			if err := validateWebhookURL(action.URL); err != nil {
+				return fmt.Errorf("action %d: %w", i+1, err)
+			}
+		default:
+			return fmt.Errorf("action %d: unknown type %q", i+1, action.Type)
+		}
+	}
+
+	checks := []struct {
+		model interface{}
+		ids   []uint
+		name  string
+	}{
+		{&models.List{}, listIDs, "list"},
+		{&models.Tag{}, tagIDs, "tag"},
+		{&models.FieldDefinition{}, fieldIDs, "field"},
+	}
+	for _, check := range checks {
+		ok, err := owned(db, check.model, check.ids, user.ID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("%s not found", check.name)
+		}
+	}
+
+	for i := range req.Actions {
+		action := &req.Actions[i]
+		if models.AutomationActionType(action.Type) != models.ActionSetField || action.FieldID == 0 {
+			continue
+		}
+		var fieldDef models.FieldDefinition
+		if err := fieldtype.WithOptions(db).Where("id = ?", action.FieldID).First(&fieldDef).Error; err != nil {
+			return err
+		}
+		value, err := fieldtype.Normalize(fieldDef, action.Value)
+		if err != nil {
+			return fmt.Errorf("action %d: %w", i+1, err)
+		}
+		if value == "" && fieldDef.Required {
+			return fmt.Errorf("action %d: %s is required", i+1, fieldDef.Name)
+		}
+		action.Value = value
+	}
+	return nil
+}
+
+func applyRuleReq(rule *models.AutomationRule, req RuleReq) {
+	rule.Name = strings.TrimSpace(req.Name)
+	rule.Enabled = req.Enabled == nil || *req.Enabled
+	rule.Trigger = req.Trigger
+	rule.TriggerListID = req.TriggerListID
+	rule.TriggerTagID = req.TriggerTagID
+	rule.TriggerFieldID = req.TriggerFieldID
+	rule.InactiveDays = req.InactiveDays
+	rule.Conditions = req.Conditions
+	rule.Actions = req.Actions
+}
+
+func toRule(rule models.AutomationRule) Rule {
+	res := Rule{
+		ID:             rule.ID,
+		Name:           rule.Name,
+		Enabled:        rule.Enabled,
+		Trigger:        rule.Trigger,
+		TriggerListID:  rule.TriggerListID,
+		TriggerTagID:   rule.TriggerTagID,
+		TriggerFieldID: rule.TriggerFieldID,
+		InactiveDays:   rule.InactiveDays,
+		Conditions:     rule.Conditions,
+		Actions:        rule.Actions,
+		CreatedAt:      rule.CreatedAt,
+		UpdatedAt:      rule.UpdatedAt,
+	}
+	if res.Conditions == nil {
+		res.Conditions = []models.AutomationCondition{}
+	}
+	if res.Actions == nil {
+		res.Actions = []models.AutomationAction{}
+	}
+	return res
+}
+
+func (s *service) findRule(id uint, user models.User) (*models.AutomationRule, error) {
+	var rule models.AutomationRule
+	s.DB.Where("id = ? AND user_id = ?", id, user.ID).First(&rule)
+	if rule.ID == 0 {
+		logger.Logger.Error("automation rule not found", zap.String("rule_id", strconv.Itoa(int(id))))
+		return nil, errors.New("rule not found")
+	}
+	return &rule, nil
+}
+
+func (s *service) CreateRule(ctx context.Context, req RuleReq, user models.User) (*Rule, error) {
+	if err := validateRule(s.DB, &req, user); err != nil {
+		return nil, err
+	}
+
+	rule := models.AutomationRule{UserID: user.ID}
+	applyRuleReq(&rule, req)
+	if err := s.DB.Create(&rule).Error; err != nil {
+		logger.Logger.Error("failed to create automation rule", zap.Error(err))
+		return nil, err
+	}
+
+	res := toRule(rule)
+	return &res, nil
+}
+
+func (s *service) GetRules(ctx context.Context, user models.User) (*GetRulesRes, error) {
+	var rules []models.AutomationRule
+	if err := s.DB.Where("user_id = ?", user.ID).Order("id ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	res := &GetRulesRes{Rules: []Rule{}}
+	for _, rule := range rules {
+		res.Rules = append(res.Rules, toRule(rule))
+	}
+	return res, nil
+}
+
+func (s *service) UpdateRule(ctx context.Context, req UpdateRuleReq, user models.User) (*Rule, error) {
+	rule, err := s.findRule(req.ID, user)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateRule(s.DB, &req.RuleReq, user); err != nil {
+		return nil, err
+	}
+
+	applyRuleReq(rule, req.RuleReq)
+	if err := s.DB.Save(rule).Error; err != nil {
+		logger.Logger.Error("failed to update automation rule", zap.Error(err))
+		return nil, err
+	}
+
+	res := toRule(*rule)
+	return &res, nil
+}
+
+func (s *service) DeleteRule(ctx context.Context, req RuleIDReq, user models.User) error {
+	rule, err := s.findRule(req.ID, user)
+	if err != nil {
+		return err
+	}
+	return s.DB.Delete(rule).Error
+}
+
+func (s *service) GetRuns(ctx context.Context, req GetRunsReq, user models.User) (*GetRunsRes, error) {
+	rule, err := s.findRule(req.ID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	query := s.DB.Where("rule_id = ?", rule.ID)
+	if req.Before != 0 {
+		query = query.Where("id < ?", req.Before)
+	}
+
+	var rows []models.AutomationRun
+	if err := query.Order("id DESC").Limit(limit + 1).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	res := &GetRunsRes{RuleID: rule.ID, Runs: []Run{}}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		res.NextBefore = rows[len(rows)-1].ID
+	}
+	for _, row := range rows {
+		res.Runs = append(res.Runs, Run{
+			ID:        row.ID,
+			CardID:    row.CardID,
+			Trigger:   row.Trigger,
+			Status:    row.Status,
+			Error:     row.Error,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return res, nil
+}
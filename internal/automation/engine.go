@@ -0,0 +1,498 @@
+package automation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Cognize-AI/client-cognize/config"
+	"github.com/Cognize-AI/client-cognize/internal/analytics"
+	"github.com/Cognize-AI/client-cognize/internal/fieldtype"
+	"github.com/Cognize-AI/client-cognize/internal/history"
+	"github.com/Cognize-AI/client-cognize/internal/stagerules"
+	"github.com/Cognize-AI/client-cognize/logger"
+	"github.com/Cognize-AI/client-cognize/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// maxChainDepth bounds how many times rules may trigger further rules.
+	maxChainDepth     = 5
+	webhookTimeout    = 10 * time.Second
+	inactiveBatchSize = 500
+)
+
+// Event is a change to a card that may trigger rules.
+type Event struct {
+	Trigger models.AutomationTrigger
+	CardID  uint
+	UserID  uint
+	// ListID is the list a moved card entered.
+	ListID  uint
+	TagID   uint
+	FieldID uint
+}
+
+// chain follows one event and everything its rules cause. A rule runs at
+// most once per card within a chain, so rules that trigger each other stop
+// instead of looping.
+type chain struct {
+	fired map[[2]uint]bool
+}
+
+func newChain() *chain {
+	return &chain{fired: map[[2]uint]bool{}}
+}
+
+// Dispatch runs the rules matching events in the background. Call it once
+// the change that caused the events is committed.
+func Dispatch(events ...Event) {
+	if len(events) == 0 || config.DB == nil {
+		return
+	}
+	go func() {
+		for _, event := range events {
+			handle(config.DB, event, newChain(), 0)
+		}
+	}()
+}
+
+func handle(db *gorm.DB, event Event, c *chain, depth int) {
+	var rules []models.AutomationRule
+	if err := db.Where("user_id = ? AND enabled AND trigger = ?", event.UserID, string(event.Trigger)).
+		Order("id ASC").
+		Find(&rules).Error; err != nil {
+		logger.Logger.Error("failed to load automation rules", zap.Error(err))
+		return
+	}
+
+	for _, rule := range rules {
+		if matchesTrigger(rule, event) {
+			runRule(db, rule, event, c, depth)
+		}
+	}
+}
+
+func matchesTrigger(rule models.AutomationRule, event Event) bool {
+	switch event.Trigger {
+	case models.TriggerCardMoved:
+		return rule.TriggerListID == 0 || rule.TriggerListID == event.ListID
+	case models.TriggerTagAdded:
+		return rule.TriggerTagID == 0 || rule.TriggerTagID == event.TagID
+	case models.TriggerFieldChanged:
+		return rule.TriggerFieldID == 0 || rule.TriggerFieldID == event.FieldID
+	}
+	return true
+}
+
+func saveRun(db *gorm.DB, run models.AutomationRun) {
+	if err := db.Create(&run).Error; err != nil {
+		logger.Logger.Error("failed to save automation run", zap.Error(err))
+	}
+}
+
+func runRule(db *gorm.DB, rule models.AutomationRule, event Event, c *chain, depth int) {
+	run := models.AutomationRun{RuleID: rule.ID, CardID: event.CardID, Trigger: string(event.Trigger)}
+
+	key := [2]uint{rule.ID, event.CardID}
+	if c.fired[key] || depth >= maxChainDepth {
+		run.Status = string(models.AutomationRunBlocked)
+		run.Error = "stopped by loop protection"
+		saveRun(db, run)
+		return
+	}
+
+	var card models.Card
+	db.Preload("List").Preload("Tags").Where("id = ?", event.CardID).First(&card)
+	if card.ID == 0 || card.List.UserID != rule.UserID {
+		return
+	}
+
+	ok, err := matchesConditions(db, rule.Conditions, card)
+	if err != nil {
+		run.Status = string(models.AutomationRunFailed)
+		run.Error = err.Error()
+		saveRun(db, run)
+		return
+	}
+	if !ok {
+		return
+	}
+	c.fired[key] = true
+
+	events, hooks, err := execute(db, rule, card)
+	if err == nil && len(hooks) > 0 {
+		err = sendWebhooks(db, rule, event, hooks)
+	}
+	run.Status = string(models.AutomationRunSuccess)
+	if err != nil {
+		run.Status = string(models.AutomationRunFailed)
+		run.Error = err.Error()
+	}
+	saveRun(db, run)
+
+	for _, child := range events {
+		handle(db, child, c, depth+1)
+	}
+}
+
+func matchesConditions(db *gorm.DB, conditions []models.AutomationCondition, card models.Card) (bool, error) {
+	var fieldIDs []uint
+	for _, cond := range conditions {
+		if cond.FieldID != 0 {
+			fieldIDs = append(fieldIDs, cond.FieldID)
+		}
+	}
+	values := map[uint]string{}
+	if len(fieldIDs) > 0 {
+		var rows []models.FieldValue
+		if err := db.Where("card_id = ? AND field_id IN ?", card.ID, fieldIDs).Find(&rows).Error; err != nil {
+			return false, err
+		}
+		for _, row := range rows {
+			values[row.FieldID] = row.Value
+		}
+	}
+	tags := map[uint]bool{}
+	for _, tag := range card.Tags {
+		tags[tag.ID] = true
+	}
+
+	for _, cond := range conditions {
+		var value string
+		switch {
+		case cond.TagID != 0:
+			if tags[cond.TagID] != (cond.Operator == "has") {
+				return false, nil
+			}
+			continue
+		case cond.FieldID != 0:
+			value = values[cond.FieldID]
+		default:
+			get, ok := models.CardFields[cond.Field]
+			if !ok {
+				return false, fmt.Errorf("unknown field %q", cond.Field)
+			}
+			value = *get(&card)
+		}
+		if !compare(cond.Operator, value, cond.Value) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// compare applies a condition operator. Text comparisons ignore case and
+// surrounding whitespace.
+func compare(operator, value, want string) bool {
+	value = strings.TrimSpace(value)
+	want = strings.TrimSpace(want)
+	switch operator {
+	case "equals":
+		return strings.EqualFold(value, want)
+	case "not_equals":
+		return !strings.EqualFold(value, want)
+	case "contains":
+		return strings.Contains(strings.ToLower(value), strings.ToLower(want))
+	case "empty":
+		return value == ""
+	case "not_empty":
+		return value != ""
+	}
+	return false
+}
+
+// execute applies the rule's actions to the card in one transaction. It
+// returns the events the actions caused and the webhooks to send once the
+// changes are committed.
+func execute(db *gorm.DB, rule models.AutomationRule, card models.Card) ([]Event, []models.AutomationAction, error) {
+	var events []Event
+	var hooks []models.AutomationAction
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, action := range rule.Actions {
+			switch models.AutomationActionType(action.Type) {
+			case models.ActionAddTag:
+				var tag models.Tag
+				tx.Where("id = ? AND user_id = ?", action.TagID, rule.UserID).First(&tag)
+				if tag.ID == 0 {
+					return fmt.Errorf("tag %d not found", action.TagID)
+				}
+				if hasTag(card, tag.ID) {
+					continue
+				}
+				if err := tx.Model(&card).Association("Tags").Append(&tag); err != nil {
+					return err
+				}
+				if err := history.Record(tx, history.Change(card.ID, rule.UserID, models.CardHistoryTagAdded, "tags", "", tag.Name)); err != nil {
+					return err
+				}
+				events = append(events, Event{Trigger: models.TriggerTagAdded, CardID: card.ID, UserID: rule.UserID, TagID: tag.ID})
+
+			case models.ActionRemoveTag:
+				if !hasTag(card, action.TagID) {
+					continue
+				}
+				var tag models.Tag
+				tx.Where("id = ?", action.TagID).First(&tag)
+				if err := tx.Model(&card).Association("Tags").Delete(&tag); err != nil {
+					return err
+				}
+				if err := history.Record(tx, history.Change(card.ID, rule.UserID, models.CardHistoryTagRemoved, "tags", tag.Name, "")); err != nil {
+					return err
+				}
+
+			case models.ActionMoveToList:
+				var list models.List
+				tx.Where("id = ? AND user_id = ?", action.ListID, rule.UserID).First(&list)
+				if list.ID == 0 {
+					return fmt.Errorf("list %d not found", action.ListID)
+				}
+				if list.ID == card.ListID {
+					continue
+				}
+				if err := stagerules.Lock(tx, &list); err != nil {
+					return err
+				}
+				if err := stagerules.Check(tx, list, card, nil); err != nil {
+					return err
+				}
+
+				var maxOrder float64
+				tx.Model(&models.Card{}).Where("list_id = ?", list.ID).Select("COALESCE(MAX(card_order), 0)").Scan(&maxOrder)
+				if err := tx.Model(&models.Card{}).Where("id = ?", card.ID).
+					Updates(map[string]interface{}{"list_id": list.ID, "card_order": maxOrder + 1}).Error; err != nil {
+					return err
+				}
+				if err := analytics.RecordTransitions(tx, analytics.Transition(card.ID, card.ListID, list.ID, rule.UserID)); err != nil {
+					return err
+				}
+				if err := history.Record(tx, history.Change(card.ID, rule.UserID, models.CardHistoryList, "list", card.List.Name, list.Name)); err != nil {
+					return err
+				}
+				card.ListID = list.ID
+				card.CardOrder = maxOrder + 1
+				card.List = list
+				events = append(events, Event{Trigger: models.TriggerCardMoved, CardID: card.ID, UserID: rule.UserID, ListID: list.ID})
+
+			case models.ActionCreateActivity:
+				if err := tx.Create(&models.Activity{Content: action.Value, CardID: card.ID}).Error; err != nil {
+					return err
+				}
+
+			case models.ActionSetField:
+				if action.FieldID == 0 {
+					get, ok := models.CardFields[action.Field]
+					if !ok {
+						return fmt.Errorf("unknown field %q", action.Field)
+					}
+					oldValue := *get(&card)
+					if oldValue == action.Value {
+						continue
+					}
+					*get(&card) = action.Value
+					if err := tx.Omit(clause.Associations).Save(&card).Error; err != nil {
+						return err
+					}
+					if err := history.Record(tx, history.Change(card.ID, rule.UserID, models.CardHistoryField, action.Field, oldValue, action.Value)); err != nil {
+						return err
+					}
+					continue
+				}
+
+				var fieldDef models.FieldDefinition
+				fieldtype.WithOptions(tx).Where("id = ? AND user_id = ?", action.FieldID, rule.UserID).First(&fieldDef)
+				if fieldDef.ID == 0 {
+					return fmt.Errorf("field %d not found", action.FieldID)
+				}
+				if fieldDef.Archived {
+					return fmt.Errorf("field %q is archived", fieldDef.Name)
+				}
+				// The field may have changed type since the rule was saved.
+				value, err := fieldtype.Normalize(fieldDef, action.Value)
+				if err != nil {
+					return fmt.Errorf("%s: %w", fieldDef.Name, err)
+				}
+				if value == "" && fieldDef.Required {
+					return fmt.Errorf("%s is required", fieldDef.Name)
+				}
+				var fieldVal models.FieldValue
+				if err := tx.Where("field_id = ? AND card_id = ?", fieldDef.ID, card.ID).Limit(1).Find(&fieldVal).Error; err != nil {
+					return err
+				}
+				if fieldVal.ID != 0 && fieldVal.Value == value {
+					continue
+				}
+				oldValue := fieldVal.Value
+				fieldVal.CardID = card.ID
+				fieldVal.FieldID = fieldDef.ID
+				fieldVal.Value = value
+				if err := tx.Omit(clause.Associations).Save(&fieldVal).Error; err != nil {
+					return err
+				}
+				if err := fieldtype.RelinkValues(tx, fieldDef, fieldVal.ID); err != nil {
+					return err
+				}
+				change := history.Change(card.ID, rule.UserID, models.CardHistoryCustomField, fieldDef.Name, oldValue, value)
+				change.FieldID = fieldDef.ID
+				if err := history.Record(tx, change); err != nil {
+					return err
+				}
+				events = append(events, Event{Trigger: models.TriggerFieldChanged, CardID: card.ID, UserID: rule.UserID, FieldID: fieldDef.ID})
+
+			case models.ActionWebhook:
+				hooks = append(hooks, action)
+
+			default:
+				return fmt.Errorf("unknown action %q", action.Type)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return events, hooks, nil
+}
+
+func hasTag(card models.Card, tagID uint) bool {
+	for _, tag := range card.Tags {
+		if tag.ID == tagID {
+			return true
+		}
+	}
+	return false
+}
+
+// webhookPayload is the JSON body posted by the WEBHOOK action.
+type webhookPayload struct {
+	RuleID   uint                   `json:"rule_id"`
+	RuleName string                 `json:"rule_name"`
+	Trigger  string                 `json:"trigger"`
+	Card     map[string]interface{} `json:"card"`
+	SentAt   time.Time              `json:"sent_at"`
+}
+
+func sendWebhooks(db *gorm.DB, rule models.AutomationRule, event Event, hooks []models.AutomationAction) error {
+	var card models.Card
+	if err := db.Preload("List").Preload("Tags").Where("id = ?", event.CardID).First(&card).Error; err != nil {
+		return err
+	}
+
+	payloadCard := map[string]interface{}{
+		"id":        card.ID,
+		"list_id":   card.ListID,
+		"list_name": card.List.Name,
+	}
+	for field, get := range models.CardFields {
+		payloadCard[field] = *get(&card)
+	}
+	tags := []string{}
+	for _, tag := range card.Tags {
+		tags = append(tags, tag.Name)
+	}
+	payloadCard["tags"] = tags
+
+	body, err := json.Marshal(webhookPayload{
+		RuleID:   rule.ID,
+		RuleName: rule.Name,
+		Trigger:  string(event.Trigger),
+		Card:     payloadCard,
+		SentAt:   time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, hook := range hooks {
+		if err := postWebhook(hook.URL, body); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func postWebhook(url string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := webhookClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook %s failed: %w", url, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned %s", url, res.Status)
+	}
+	return nil
+}
+
+// lastActivity is the SQL time of a card's latest change or activity note.
+const lastActivity = "GREATEST(cards.updated_at, (SELECT MAX(activities.created_at) FROM activities WHERE activities.card_id = cards.id AND activities.deleted_at IS NULL))"
+
+// StartScheduler fires NO_ACTIVITY rules once at startup and then every
+// interval.
+func StartScheduler(db *gorm.DB, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := RunInactivity(db, time.Now()); err != nil {
+				logger.Logger.Error("automation scheduler failed", zap.Error(err))
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// RunInactivity fires each NO_ACTIVITY rule for the cards that have not been
+// changed or received an activity note for the rule's number of days. A card
+// fires a rule once until it sees activity again.
+func RunInactivity(db *gorm.DB, now time.Time) error {
+	var rules []models.AutomationRule
+	if err := db.Where("enabled AND trigger = ? AND inactive_days > 0", string(models.TriggerNoActivity)).
+		Order("id ASC").
+		Find(&rules).Error; err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		cutoff := now.AddDate(0, 0, -rule.InactiveDays)
+		var lastID uint
+		for {
+			query := db.Model(&models.Card{}).
+				Joins("JOIN lists ON lists.id = cards.list_id AND lists.deleted_at IS NULL").
+				Where("lists.user_id = ? AND cards.id > ?", rule.UserID, lastID).
+				Where(lastActivity+" < ?", cutoff).
+				Where("NOT EXISTS (SELECT 1 FROM automation_runs WHERE automation_runs.rule_id = ? AND automation_runs.card_id = cards.id AND automation_runs.created_at > "+lastActivity+")", rule.ID)
+			if rule.TriggerListID != 0 {
+				query = query.Where("cards.list_id = ?", rule.TriggerListID)
+			}
+
+			var cardIDs []uint
+			if err := query.Order("cards.id ASC").Limit(inactiveBatchSize).Pluck("cards.id", &cardIDs).Error; err != nil {
+				return err
+			}
+			for _, cardID := range cardIDs {
+				event := Event{Trigger: models.TriggerNoActivity, CardID: cardID, UserID: rule.UserID}
+				runRule(db, rule, event, newChain(), 0)
+			}
+			if len(cardIDs) < inactiveBatchSize {
+				break
+			}
+			lastID = cardIDs[len(cardIDs)-1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package automation
+
+import (
+	"testing"
+
+	"github.com/Cognize-AI/client-cognize/models"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		operator, value, want string
+		match                 bool
+	}{
+		{"equals", " Acme ", "acme", true},
+		{"equals", "Acme Inc", "acme", false},
+		{"not_equals", "Acme", "ACME", false},
+		{"not_equals", "Acme", "Zeta", true},
+		{"contains", "Head of Sales", "SALES", true},
+		{"contains", "Head of Sales", "marketing", false},
+		{"empty", "  ", "", true},
+		{"empty", "x", "", false},
+		{"not_empty", "x", "", true},
+		{"not_empty", "", "", false},
+		{"unknown", "x", "x", false},
+	}
+	for _, tt := range tests {
+		if got := compare(tt.operator, tt.value, tt.want); got != tt.match {
+			t.Errorf("compare(%q, %q, %q) = %v, want %v", tt.operator, tt.value, tt.want, got, tt.match)
+		}
+	}
+}
+
+func TestMatchesTrigger(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  models.AutomationRule
+		event Event
+		match bool
+	}{
+		{"any list", models.AutomationRule{}, Event{Trigger: models.TriggerCardMoved, ListID: 4}, true},
+		{"same list", models.AutomationRule{TriggerListID: 4}, Event{Trigger: models.TriggerCardMoved, ListID: 4}, true},
+		{"other list", models.AutomationRule{TriggerListID: 4}, Event{Trigger: models.TriggerCardMoved, ListID: 5}, false},
+		{"same tag", models.AutomationRule{TriggerTagID: 2}, Event{Trigger: models.TriggerTagAdded, TagID: 2}, true},
+		{"other tag", models.AutomationRule{TriggerTagID: 2}, Event{Trigger: models.TriggerTagAdded, TagID: 3}, false},
+		{"same field", models.AutomationRule{TriggerFieldID: 7}, Event{Trigger: models.TriggerFieldChanged, FieldID: 7}, true},
+		{"other field", models.AutomationRule{TriggerFieldID: 7}, Event{Trigger: models.TriggerFieldChanged, FieldID: 8}, false},
+		{"card created", models.AutomationRule{TriggerListID: 4}, Event{Trigger: models.TriggerCardCreated}, true},
+	}
+	for _, tt := range tests {
+		if got := matchesTrigger(tt.rule, tt.event); got != tt.match {
+			t.Errorf("%s: matchesTrigger = %v, want %v", tt.name, got, tt.match)
+		}
+	}
+}
+
+func TestMatchesConditions(t *testing.T) {
+	tag := models.Tag{Name: "VIP"}
+	tag.ID = 3
+	card := models.Card{Name: "Jane Doe", Email: "jane@acme.com", Tags: []models.Tag{tag}}
+	card.ID = 1
+
+	tests := []struct {
+		name       string
+		conditions []models.AutomationCondition
+		match      bool
+		wantErr    bool
+	}{
+		{"no conditions", nil, true, false},
+		{"all conditions hold", []models.AutomationCondition{
+			{Field: "email", Operator: "contains", Value: "@acme"},
+			{Field: "phone", Operator: "empty"},
+			{TagID: 3, Operator: "has"},
+		}, true, false},
+		{"one condition fails", []models.AutomationCondition{
+			{Field: "email", Operator: "contains", Value: "@acme"},
+			{Field: "name", Operator: "equals", Value: "John Doe"},
+		}, false, false},
+		{"tag missing", []models.AutomationCondition{{TagID: 4, Operator: "has"}}, false, false},
+		{"tag absent", []models.AutomationCondition{{TagID: 4, Operator: "not_has"}}, true, false},
+		{"tag present but excluded", []models.AutomationCondition{{TagID: 3, Operator: "not_has"}}, false, false},
+		{"unknown field", []models.AutomationCondition{{Field: "password", Operator: "empty"}}, false, true},
+	}
+	for _, tt := range tests {
+		// Without custom field conditions no values are loaded, so no
+		// database is needed.
+		got, err := matchesConditions(nil, tt.conditions, card)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got != tt.match {
+			t.Errorf("%s: matchesConditions = %v, want %v", tt.name, got, tt.match)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package automation
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+)
+
+// webhookClient only connects to public addresses. The check runs on the
+// resolved IP of every connection, so a host name pointing at an internal
+// address is refused too. Redirects are not followed; the 3xx response fails
+// the webhook.
+var webhookClient = &http.Client{
+	Timeout: webhookTimeout,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: webhookTimeout,
+			Control: publicOnly,
+		}).DialContext,
+		TLSHandshakeTimeout: webhookTimeout,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// reservedNets are ranges net.IP has no predicate for: "this network"
+// 0.0.0.0/8 and carrier-grade NAT 100.64.0.0/10, which cloud VPCs and
+// metadata proxies use internally.
+var reservedNets = []*net.IPNet{
+	mustCIDR("0.0.0.0/8"),
+	mustCIDR("100.64.0.0/10"),
+}
+
+func mustCIDR(s string) *net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// internalIP reports whether ip is loopback, private, link-local (including
+// the cloud metadata address 169.254.169.254), multicast, unspecified or in
+// one of reservedNets.
+func internalIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+	for _, n := range reservedNets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func publicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || internalIP(ip) {
+		return fmt.Errorf("webhook address %s is not public", host)
+	}
+	return nil
+}
+
+// validateWebhookURL checks a webhook URL when a rule is saved. Hosts must be
+// names; IP literals and localhost are refused.
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be an http or https URL")
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "" {
+		return errors.New("url must be an http or https URL")
+	}
+	if net.ParseIP(host) != nil {
+		return errors.New("url must use a host name, not an IP address")
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return errors.New("url cannot point at localhost")
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package automation
+
+import "testing"
+
+func TestPublicOnly(t *testing.T) {
+	tests := []struct {
+		address string
+		public  bool
+	}{
+		{"93.184.216.34:443", true},
+		{"[2606:2800:220:1:248:1893:25c8:1946]:443", true},
+		{"100.63.255.255:80", true},
+		{"100.128.0.1:80", true},
+		{"127.0.0.1:80", false},
+		{"10.1.2.3:80", false},
+		{"172.16.0.1:80", false},
+		{"192.168.1.1:80", false},
+		{"169.254.169.254:80", false},
+		{"0.0.0.0:80", false},
+		{"0.1.2.3:80", false},
+		{"100.64.0.1:80", false},
+		{"100.127.255.254:80", false},
+		{"224.0.0.1:80", false},
+		{"[::1]:80", false},
+		{"[fd00::1]:80", false},
+		{"[fe80::1]:80", false},
+		{"[::ffff:127.0.0.1]:80", false},
+		{"[::ffff:100.64.0.1]:80", false},
+		{"example.com:80", false},
+		{"no-port", false},
+	}
+	for _, tt := range tests {
+		err := publicOnly("tcp", tt.address, nil)
+		if got := err == nil; got != tt.public {
+			t.Errorf("publicOnly(%q) = %v, want public %v", tt.address, err, tt.public)
+		}
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://hooks.example.com/abc", true},
+		{"http://example.com:8080/hook?x=1", true},
+		{"ftp://example.com/hook", false},
+		{"example.com/hook", false},
+		{"https://", false},
+		{"https://127.0.0.1/hook", false},
+		{"https://100.64.0.1/hook", false},
+		{"https://[::1]/hook", false},
+		{"https://localhost/hook", false},
+		{"https://LOCALHOST./hook", false},
+		{"https://api.localhost/hook", false},
+		{"://bad", false},
+	}
+	for _, tt := range tests {
+		err := validateWebhookURL(tt.url)
+		if got := err == nil; got != tt.valid {
+			t.Errorf("validateWebhookURL(%q) = %v, want valid %v", tt.url, err, tt.valid)
+		}
+	}
+}
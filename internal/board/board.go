@@ -0,0 +1,47 @@
+package board
+
+import (
+	"context"
+	"time"
+
+	"github.com/Cognize-AI/client-cognize/models"
+)
+
+type BoardResp struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	IsDefault bool      `json:"is_default"`
+	ListCount int64     `json:"list_count"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type GetBoardsRes struct {
+	Boards []BoardResp `json:"boards"`
+}
+
+type CreateBoardReq struct {
+	Name string `json:"name" binding:"required"`
+	// Template is a built-in pipeline template and TemplateID a saved one.
+	// The sales template is used when neither is set.
+	Template   string `json:"template"`
+	TemplateID uint   `json:"template_id"`
+	// Empty skips the template and creates no lists.
+	Empty bool `json:"empty"`
+}
+
+type UpdateBoardReq struct {
+	ID   uint   `uri:"id" binding:"required"`
+	Name string `json:"name" binding:"required"`
+}
+
+type BoardIDReq struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+type Service interface {
+	CreateBoard(ctx context.Context, req CreateBoardReq, user models.User) (*BoardResp, error)
+	GetBoards(ctx context.Context, user models.User) (*GetBoardsRes, error)
+	UpdateBoard(ctx context.Context, req UpdateBoardReq, user models.User) (*BoardResp, error)
+	DeleteBoard(ctx context.Context, req BoardIDReq, user models.User) error
+}
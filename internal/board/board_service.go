@@ -0,0 +1,185 @@
+package board
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Cognize-AI/client-cognize/config"
+	"github.com/Cognize-AI/client-cognize/internal/pipeline"
+	"github.com/Cognize-AI/client-cognize/logger"
+	"github.com/Cognize-AI/client-cognize/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type service struct {
+	timeout time.Duration
+	DB      *gorm.DB
+}
+
+func NewService() Service {
+	return &service{
+		time.Duration(20) * time.Second,
+		config.DB,
+	}
+}
+
+// DefaultBoard returns the user's default board, creating it if needed.
+// Concurrent callers create it once: the insert yields to the unique index on
+// the user's default board (see db.SyncDB) and everyone reads the same row.
+func DefaultBoard(db *gorm.DB, userID uint) (*models.Board, error) {
+	var board models.Board
+	db.Where("user_id = ? AND is_default", userID).First(&board)
+	if board.ID != 0 {
+		return &board, nil
+	}
+
+	if err := db.Exec(`
+		INSERT INTO boards (name, user_id, is_default, created_at, updated_at)
+		VALUES (?, ?, true, now(), now())
+		ON CONFLICT (user_id) WHERE is_default AND deleted_at IS NULL DO NOTHING`,
+		models.DefaultBoardName, userID).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("user_id = ? AND is_default", userID).First(&board).Error; err != nil {
+		return nil, err
+	}
+	return &board, nil
+}
+
+// FindBoard loads a board of the user. A zero id selects the default board.
+func FindBoard(db *gorm.DB, id, userID uint) (*models.Board, error) {
+	if id == 0 {
+		return DefaultBoard(db, userID)
+	}
+
+	var board models.Board
+	db.Where("id = ? AND user_id = ?", id, userID).First(&board)
+	if board.ID == 0 {
+		logger.Logger.Error("board not found", zap.String("board_id", strconv.Itoa(int(id))))
+		return nil, errors.New("board not found")
+	}
+	return &board, nil
+}
+
+func toBoardResp(board models.Board, listCount int64) *BoardResp {
+	return &BoardResp{
+		ID:        board.ID,
+		Name:      board.Name,
+		IsDefault: board.IsDefault,
+		ListCount: listCount,
+		CreatedAt: board.CreatedAt,
+		UpdatedAt: board.UpdatedAt,
+	}
+}
+
+func (s *service) CreateBoard(ctx context.Context, req CreateBoardReq, user models.User) (*BoardResp, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("board name is required")
+	}
+
+	var template *pipeline.Template
+	if !req.Empty {
+		var err error
+		template, err = pipeline.Resolve(s.DB, req.Template, req.TemplateID, user.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Make sure the default board exists before any other is created.
+	if _, err := DefaultBoard(s.DB, user.ID); err != nil {
+		return nil, err
+	}
+
+	board := models.Board{Name: name, UserID: user.ID}
+	var lists []models.List
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&board).Error; err != nil {
+			return err
+		}
+		if template == nil {
+			return nil
+		}
+		var err error
+		lists, err = pipeline.Apply(tx, *template, user.ID, board.ID)
+		return err
+	})
+	if err != nil {
+		logger.Logger.Error("failed to create board", zap.Error(err))
+		return nil, fmt.Errorf("failed to create board: %w", err)
+	}
+
+	return toBoardResp(board, int64(len(lists))), nil
+}
+
+func (s *service) GetBoards(ctx context.Context, user models.User) (*GetBoardsRes, error) {
+	if _, err := DefaultBoard(s.DB, user.ID); err != nil {
+		return nil, err
+	}
+
+	boards := []BoardResp{}
+	err := s.DB.Model(&models.Board{}).
+		Select("boards.id, boards.name, boards.is_default, boards.created_at, boards.updated_at, COUNT(lists.id) AS list_count").
+		Joins("LEFT JOIN lists ON lists.board_id = boards.id AND lists.deleted_at IS NULL").
+		Where("boards.user_id = ?", user.ID).
+		Group("boards.id").
+		Order("boards.is_default DESC, boards.id ASC").
+		Scan(&boards).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetBoardsRes{Boards: boards}, nil
+}
+
+func (s *service) UpdateBoard(ctx context.Context, req UpdateBoardReq, user models.User) (*BoardResp, error) {
+	board, err := FindBoard(s.DB, req.ID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("board name is required")
+	}
+	board.Name = name
+	if err := s.DB.Save(board).Error; err != nil {
+		logger.Logger.Error("failed to update board", zap.Error(err))
+		return nil, fmt.Errorf("failed to update board: %w", err)
+	}
+
+	var listCount int64
+	s.DB.Model(&models.List{}).Where("board_id = ?", board.ID).Count(&listCount)
+
+	return toBoardResp(*board, listCount), nil
+}
+
+// DeleteBoard deletes an empty board. Lists, including trashed ones, must be
+// deleted or purged first so none is left pointing at a deleted board.
+func (s *service) DeleteBoard(ctx context.Context, req BoardIDReq, user models.User) error {
+	board, err := FindBoard(s.DB, req.ID, user.ID)
+	if err != nil {
+		return err
+	}
+	if board.IsDefault {
+		return errors.New("the default board cannot be deleted")
+	}
+
+	var listCount int64
+	s.DB.Unscoped().Model(&models.List{}).Where("board_id = ?", board.ID).Count(&listCount)
+	if listCount > 0 {
+		return errors.New("board still has lists, delete them and empty the trash first")
+	}
+
+	if err := s.DB.Delete(board).Error; err != nil {
+		logger.Logger.Error("failed to delete board", zap.Error(err))
+		return fmt.Errorf("failed to delete board: %w", err)
+	}
+	return nil
+}
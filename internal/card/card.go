@@ -20,6 +20,12 @@ type GetCard struct {
 	Tags        []tag.RespTag `json:"tags"`
 }
 
+// FieldInput sets a custom field of a card.
+type FieldInput struct {
+	FieldID uint   `json:"field_id"`
+	Value   string `json:"value"`
+}
+
 type CreateCardReq struct {
 	Name        string `json:"name"`
 	Designation string `json:"designation"`
@@ -27,6 +33,8 @@ type CreateCardReq struct {
 	Phone       string `json:"phone"`
 	ImageURL    string `json:"image_url"`
 	ListID      uint   `json:"list_id"`
+	// Fields must include every required custom field without a default.
+	Fields []FieldInput `json:"fields"`
 }
 
 type CreateCardResp struct {
@@ -48,6 +56,28 @@ type DeleteCardResp struct {
 	ID uint `json:"id"`
 }
 
+type TrashCard struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	ImageURL  string    `json:"image_url"`
+	ListID    uint      `json:"list_id"`
+	ListName  string    `json:"list_name"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type GetTrashResp struct {
+	Cards []TrashCard `json:"cards"`
+}
+
+type RestoreCardReq struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+type RestoreCardResp struct {
+	ID uint `json:"id"`
+}
+
 type UpdateCardReq struct {
 	ID          uint   `uri:"id" binding:"required"`
 	Name        string `json:"name"`
@@ -63,17 +93,29 @@ type UpdateCardResp struct {
 }
 
 type BulkProspect struct {
-	Name        string `json:"name"`
-	Designation string `json:"designation"`
-	Email       string `json:"email"`
-	Phone       string `json:"phone"`
-	ImageURL    string `json:"image_url"`
-	ProfileURL  string `json:"profile_url"`
-	AISummary   string `json:"ai_summary"`
+	Name        string       `json:"name"`
+	Designation string       `json:"designation"`
+	Email       string       `json:"email"`
+	Phone       string       `json:"phone"`
+	ImageURL    string       `json:"image_url"`
+	ProfileURL  string       `json:"profile_url"`
+	AISummary   string       `json:"ai_summary"`
+	Fields      []FieldInput `json:"fields"`
 }
 
+type BulkMode string
+
+const (
+	BulkModeInsert BulkMode = "insert"
+	BulkModeUpsert BulkMode = "upsert"
+)
+
 type BulkCreateReq struct {
+	// BoardID restricts ListID to a board. With BoardID alone, prospects go to
+	// the board's first list.
+	BoardID   uint           `json:"board_id"`
 	ListID    uint           `json:"list_id"`
+	Mode      BulkMode       `json:"mode"`
 	Prospects []BulkProspect `json:"prospects"`
 }
 
@@ -86,13 +128,20 @@ type ContactDetails struct {
 	Name     string `json:"name"`
 	Value    string `json:"value"`
 	DataType string `json:"data_type"`
+	// TypedValue is Value decoded for its data type, see fieldtype.Typed.
+	TypedValue interface{} `json:"typed_value"`
+	Currency   string      `json:"currency,omitempty"`
+	Required   bool        `json:"required"`
 }
 
 type CompanyDetails struct {
-	ID       uint   `json:"id"`
-	Name     string `json:"name"`
-	Value    string `json:"value"`
-	DataType string `json:"data_type"`
+	ID         uint        `json:"id"`
+	Name       string      `json:"name"`
+	Value      string      `json:"value"`
+	DataType   string      `json:"data_type"`
+	TypedValue interface{} `json:"typed_value"`
+	Currency   string      `json:"currency,omitempty"`
+	Required   bool        `json:"required"`
 }
 
 type GetCardCompanyDetails struct {
@@ -122,7 +171,28 @@ type GetCardByIDResp struct {
 	Activity          []GetCardActivity     `json:"activity"`
 }
 
+type BulkRowStatus string
+
+const (
+	BulkRowCreated BulkRowStatus = "created"
+	BulkRowUpdated BulkRowStatus = "updated"
+	BulkRowSkipped BulkRowStatus = "skipped"
+	BulkRowFailed  BulkRowStatus = "failed"
+)
+
+type BulkRowResult struct {
+	Index   int           `json:"index"`
+	Status  BulkRowStatus `json:"status"`
+	CardID  uint          `json:"card_id,omitempty"`
+	Reasons []string      `json:"reasons,omitempty"`
+}
+
 type BulkCreateResp struct {
+	Created int             `json:"created"`
+	Updated int             `json:"updated"`
+	Skipped int             `json:"skipped"`
+	Failed  int             `json:"failed"`
+	Results []BulkRowResult `json:"results"`
 }
 
 type UpdateCardByIDReq struct {
@@ -138,18 +208,156 @@ type UpdateCardByIDReq struct {
 	CompanyLocation string `json:"company_location"`
 	CompanyPhone    string `json:"company_phone"`
 	CompanyEmail    string `json:"company_email"`
+	// Fields updates custom fields. Other fields keep their value.
+	Fields []FieldInput `json:"fields"`
 }
 
 type UpdateCardByIDResp struct {
 	ID uint `json:"id"`
 }
 
+type DuplicateReason string
+
+const (
+	DuplicateReasonEmail       DuplicateReason = "email"
+	DuplicateReasonPhone       DuplicateReason = "phone"
+	DuplicateReasonProfileURL  DuplicateReason = "profile_url"
+	DuplicateReasonNameCompany DuplicateReason = "name_company"
+)
+
+type DuplicateCard struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Email       string    `json:"email"`
+	Phone       string    `json:"phone"`
+	ProfileURL  string    `json:"profile_url"`
+	CompanyName string    `json:"company_name"`
+	ListID      uint      `json:"list_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type DuplicateGroup struct {
+	Reasons []DuplicateReason `json:"reasons"`
+	Cards   []DuplicateCard   `json:"cards"`
+}
+
+type FindDuplicatesResp struct {
+	Groups []DuplicateGroup `json:"groups"`
+}
+
+type MergeCardReq struct {
+	SurvivorID uint   `json:"survivor_id" binding:"required"`
+	MergeIDs   []uint `json:"merge_ids" binding:"required"`
+	// Fields maps a built-in field (e.g. "email", "company_name") to the id of
+	// the card whose value should be kept. Fields left out keep the survivor's
+	// value, falling back to the first non-empty value among the merged cards.
+	Fields map[string]uint `json:"fields"`
+}
+
+type MergeCardResp struct {
+	ID        uint   `json:"id"`
+	MergedIDs []uint `json:"merged_ids"`
+}
+
+type ExportVCardReq struct {
+	CardID  uint   `uri:"id"`
+	ListID  uint   `form:"list_id"`
+	Version string `form:"version"`
+}
+
+type ExportVCardResp struct {
+	FileName string
+	Content  []byte
+}
+
+type ImportVCardReq struct {
+	ListID  uint
+	Content []byte
+}
+
+type ImportVCardResp struct {
+	Created int    `json:"created"`
+	Skipped int    `json:"skipped"`
+	Failed  int    `json:"failed"`
+	CardIDs []uint `json:"card_ids"`
+	// Errors lists the contacts left out because they do not meet the
+	// list's entry rules.
+	Errors []string `json:"errors"`
+	// InvalidValues lists the values left out because they do not fit the
+	// data type of their field or the field is archived.
+	InvalidValues []string `json:"invalid_values"`
+}
+
+type BulkOperation string
+
+const (
+	BulkOpMove       BulkOperation = "move"
+	BulkOpAddTags    BulkOperation = "add_tags"
+	BulkOpRemoveTags BulkOperation = "remove_tags"
+	BulkOpSetField   BulkOperation = "set_field"
+	BulkOpDelete     BulkOperation = "delete"
+)
+
+// BulkCardFilter selects the user's cards by list and/or tag. Cards must match
+// every given condition and at least one of TagIDs.
+type BulkCardFilter struct {
+	ListID uint   `json:"list_id"`
+	TagIDs []uint `json:"tag_ids"`
+}
+
+type BulkCardReq struct {
+	// Either CardIDs or Filter selects the cards to operate on.
+	CardIDs   []uint          `json:"card_ids"`
+	Filter    *BulkCardFilter `json:"filter"`
+	Operation BulkOperation   `json:"operation" binding:"required"`
+	// ListID is the target list of a move.
+	ListID uint `json:"list_id"`
+	// TagIDs are added or removed by add_tags and remove_tags.
+	TagIDs []uint `json:"tag_ids"`
+	// FieldID and Value are written by set_field.
+	FieldID uint   `json:"field_id"`
+	Value   string `json:"value"`
+}
+
+type BulkCardStatus string
+
+const (
+	BulkCardUpdated   BulkCardStatus = "updated"
+	BulkCardUnchanged BulkCardStatus = "unchanged"
+	BulkCardNotFound  BulkCardStatus = "not_found"
+	// BulkCardRejected is used when a move breaks the target list's rules.
+	BulkCardRejected BulkCardStatus = "rejected"
+)
+
+type BulkCardResult struct {
+	CardID  uint           `json:"card_id"`
+	Status  BulkCardStatus `json:"status"`
+	Reasons []string       `json:"reasons,omitempty"`
+}
+
+type BulkCardResp struct {
+	Operation BulkOperation    `json:"operation"`
+	Updated   int              `json:"updated"`
+	Unchanged int              `json:"unchanged"`
+	NotFound  int              `json:"not_found"`
+	Rejected  int              `json:"rejected"`
+	Results   []BulkCardResult `json:"results"`
+}
+
 type Service interface {
 	CreateCard(ctx context.Context, req CreateCardReq, user models.User) (*CreateCardResp, error)
 	MoveCard(ctx context.Context, req MoveCardReq, user models.User) error
 	DeleteCard(ctx context.Context, req DeleteCardReq, user models.User) (*DeleteCardResp, error)
+	GetTrash(ctx context.Context, user models.User) (*GetTrashResp, error)
+	RestoreCard(ctx context.Context, req RestoreCardReq, user models.User) (*RestoreCardResp, error)
+	DeleteCardPermanently(ctx context.Context, req DeleteCardReq, user models.User) (*DeleteCardResp, error)
 	UpdateCard(ctx context.Context, req UpdateCardReq, user models.User) (*UpdateCardResp, error)
 	BulkCreate(ctx context.Context, req BulkCreateReq, key models.Key) (*BulkCreateResp, error)
 	GetCardByID(ctx context.Context, req GetCardByIDReq, user models.User) (*GetCardByIDResp, error)
 	UpdateCardByID(ctx context.Context, req UpdateCardByIDReq, user models.User) (*UpdateCardByIDResp, error)
+	FindDuplicates(ctx context.Context, user models.User) (*FindDuplicatesResp, error)
+	MergeCards(ctx context.Context, req MergeCardReq, user models.User) (*MergeCardResp, error)
+	BulkUpdate(ctx context.Context, req BulkCardReq, user models.User) (*BulkCardResp, error)
+	ExportVCard(ctx context.Context, req ExportVCardReq, user models.User) (*ExportVCardResp, error)
+	ImportVCard(ctx context.Context, req ImportVCardReq, user models.User) (*ImportVCardResp, error)
 }
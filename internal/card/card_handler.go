@@ -1,8 +1,13 @@
 package card
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 
+	"github.com/Cognize-AI/client-cognize/internal/stagerules"
 	"github.com/Cognize-AI/client-cognize/logger"
 	"github.com/Cognize-AI/client-cognize/models"
 	"github.com/Cognize-AI/client-cognize/util"
@@ -10,6 +15,8 @@ import (
 	"go.uber.org/zap"
 )
 
+const maxVCardSize = 10 << 20
+
 type Handler struct {
 	Service
 }
@@ -18,6 +25,17 @@ func NewHandler(s Service) *Handler {
 	return &Handler{s}
 }
 
+// writeStageRuleError responds with the unmet list requirements when err is a
+// stagerules.Error.
+func writeStageRuleError(c *gin.Context, err error) bool {
+	var ruleErr *stagerules.Error
+	if !errors.As(err, &ruleErr) {
+		return false
+	}
+	c.JSON(http.StatusUnprocessableEntity, gin.H{"error": ruleErr.Error(), "details": ruleErr})
+	return true
+}
+
 func (h *Handler) CreateCard(c *gin.Context) {
 	user, exists := c.Get("user")
 	if !exists {
@@ -34,6 +52,9 @@ func (h *Handler) CreateCard(c *gin.Context) {
 	}
 
 	res, err := h.Service.CreateCard(c, req, currentUser)
+	if writeStageRuleError(c, err) {
+		return
+	}
 	if err != nil {
 		logger.Logger.Error("Error creating card :", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -60,6 +81,9 @@ func (h *Handler) MoveCard(c *gin.Context) {
 	}
 
 	err := h.Service.MoveCard(c, req, currentUser)
+	if writeStageRuleError(c, err) {
+		return
+	}
 	if err != nil {
 		logger.Logger.Error("Error moving card :", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -134,13 +158,14 @@ func (h *Handler) BulkCreate(c *gin.Context) {
 		return
 	}
 
-	_, err := h.Service.BulkCreate(c, req, key)
+	res, err := h.Service.BulkCreate(c, req, key)
 	if err != nil {
 		logger.Logger.Error("Error creating card :", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": "ok"})
+	c.JSON(http.StatusOK, gin.H{"data": res})
 }
 
 func (h *Handler) GetCardById(c *gin.Context) {
@@ -193,3 +218,224 @@ func (h *Handler) UpdateCardByID(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"data": "ok"})
 }
+
+func (h *Handler) FindDuplicates(c *gin.Context) {
+	currentUser, valid := util.GetCurrentUser(c)
+	if !valid {
+		return
+	}
+
+	res, err := h.Service.FindDuplicates(c, currentUser)
+	if err != nil {
+		logger.Logger.Error("Error finding duplicate cards :", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+func (h *Handler) MergeCards(c *gin.Context) {
+	currentUser, valid := util.GetCurrentUser(c)
+	if !valid {
+		return
+	}
+
+	var req MergeCardReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Logger.Warn("Failed to bind json :", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.Service.MergeCards(c, req, currentUser)
+	if err != nil {
+		logger.Logger.Error("Error merging cards :", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+func (h *Handler) BulkUpdate(c *gin.Context) {
+	currentUser, valid := util.GetCurrentUser(c)
+	if !valid {
+		return
+	}
+
+	var req BulkCardReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Logger.Warn("Failed to bind json :", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.Service.BulkUpdate(c, req, currentUser)
+	if err != nil {
+		logger.Logger.Error("Error running bulk card operation :", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+func (h *Handler) exportVCard(c *gin.Context, req ExportVCardReq, currentUser models.User) {
+	res, err := h.Service.ExportVCard(c, req, currentUser)
+	if err != nil {
+		logger.Logger.Error("Error exporting vcard :", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", res.FileName))
+	c.Data(http.StatusOK, "text/vcard; charset=utf-8", res.Content)
+}
+
+func (h *Handler) ExportCardVCard(c *gin.Context) {
+	currentUser, valid := util.GetCurrentUser(c)
+	if !valid {
+		return
+	}
+
+	var req ExportVCardReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.ListID = 0
+
+	h.exportVCard(c, req, currentUser)
+}
+
+func (h *Handler) ExportListVCard(c *gin.Context) {
+	currentUser, valid := util.GetCurrentUser(c)
+	if !valid {
+		return
+	}
+
+	var req ExportVCardReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.ListID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "list_id is required"})
+		return
+	}
+
+	h.exportVCard(c, req, currentUser)
+}
+
+func (h *Handler) ImportVCard(c *gin.Context) {
+	currentUser, valid := util.GetCurrentUser(c)
+	if !valid {
+		return
+	}
+
+	listID, err := strconv.Atoi(c.PostForm("list_id"))
+	if err != nil || listID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "list_id is required"})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		logger.Logger.Warn("Failed to read form file :", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer f.Close()
+
+	content, err := io.ReadAll(io.LimitReader(f, maxVCardSize+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(content) > maxVCardSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is larger than 10MB"})
+		return
+	}
+
+	res, err := h.Service.ImportVCard(c, ImportVCardReq{ListID: uint(listID), Content: content}, currentUser)
+	if err != nil {
+		logger.Logger.Error("Error importing vcard :", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+func (h *Handler) GetTrash(c *gin.Context) {
+	currentUser, valid := util.GetCurrentUser(c)
+	if !valid {
+		return
+	}
+
+	res, err := h.Service.GetTrash(c, currentUser)
+	if err != nil {
+		logger.Logger.Error("Error getting trashed cards :", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+func (h *Handler) RestoreCard(c *gin.Context) {
+	currentUser, valid := util.GetCurrentUser(c)
+	if !valid {
+		return
+	}
+
+	var req RestoreCardReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.Service.RestoreCard(c, req, currentUser)
+	if writeStageRuleError(c, err) {
+		return
+	}
+	if err != nil {
+		logger.Logger.Error("Error restoring card :", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+func (h *Handler) DeleteCardPermanently(c *gin.Context) {
+	currentUser, valid := util.GetCurrentUser(c)
+	if !valid {
+		return
+	}
+
+	var req DeleteCardReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.Service.DeleteCardPermanently(c, req, currentUser)
+	if err != nil {
+		logger.Logger.Error("Error deleting card permanently :", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
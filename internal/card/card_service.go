@@ -1,17 +1,29 @@
 package card
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"math"
+	"net/mail"
+	"net/url"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Cognize-AI/client-cognize/config"
+	"github.com/Cognize-AI/client-cognize/internal/analytics"
+	"github.com/Cognize-AI/client-cognize/internal/automation"
+	"github.com/Cognize-AI/client-cognize/internal/board"
+	"github.com/Cognize-AI/client-cognize/internal/fieldtype"
+	"github.com/Cognize-AI/client-cognize/internal/history"
+	"github.com/Cognize-AI/client-cognize/internal/stagerules"
 	"github.com/Cognize-AI/client-cognize/internal/tag"
 	"github.com/Cognize-AI/client-cognize/logger"
 	"github.com/Cognize-AI/client-cognize/models"
+	"github.com/Cognize-AI/client-cognize/util"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -49,6 +61,7 @@ func (s *service) CreateCard(ctx context.Context, req CreateCardReq, user models
 	s.DB.Where("id = ? AND user_id = ?", req.ListID, user.ID).First(&list)
 	if list.ID == 0 {
 		logger.Logger.Error("list not found", zap.String("list_id", strconv.Itoa(int(req.ListID))))
+		return nil, errors.New("list not found")
 	}
 
 	var maxOrder float64
@@ -63,7 +76,36 @@ func (s *service) CreateCard(ctx context.Context, req CreateCardReq, user models
 		ListID:      req.ListID,
 		CardOrder:   maxOrder + 1,
 	}
-	s.DB.Create(&card)
+	fields, err := loadUserFields(s.DB, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := fields.plan(req.Fields, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := stagerules.Lock(tx, &list); err != nil {
+			return err
+		}
+		// The list's required custom fields are checked against the values
+		// the card is created with, defaults included.
+		if err := stagerules.Check(tx, list, card, changes.values); err != nil {
+			return err
+		}
+		if err := tx.Create(&card).Error; err != nil {
+			return err
+		}
+		if err := changes.write(tx, card.ID, user.ID); err != nil {
+			return err
+		}
+		return analytics.RecordTransitions(tx, analytics.Transition(card.ID, 0, list.ID, user.ID))
+	})
+	if err != nil {
+		logger.Logger.Error("Error creating card", zap.Error(err))
+		return nil, fmt.Errorf("failed to create card: %w", err)
+	}
+	automation.Dispatch(automation.Event{Trigger: models.TriggerCardCreated, CardID: card.ID, UserID: user.ID})
 
 	return &CreateCardResp{card.ID}, nil
 }
@@ -89,11 +131,24 @@ func (s *service) MoveCard(ctx context.Context, req MoveCardReq, user models.Use
 	}
 
 	if err := s.DB.
+		Preload("List").
 		Where("id = ?", req.CurrCard).
 		First(&currCard).Error; err != nil {
 		return fmt.Errorf("current card not found: %w", err)
 	}
+	if currCard.List.UserID != user.ID {
+		return errors.New("current card not found")
+	}
+
+	var list models.List
+	s.DB.Where("id = ? AND user_id = ?", req.ListID, user.ID).First(&list)
+	if list.ID == 0 {
+		return errors.New("list not found")
+	}
+
+	prevListID := currCard.ListID
 	currCard.ListID = req.ListID
+	currCard.List = models.List{}
 
 	// Decide new order
 	if req.PrevCard != 0 && req.NextCard != 0 {
@@ -126,10 +181,41 @@ func (s *service) MoveCard(ctx context.Context, req MoveCardReq, user models.Use
 		currCard.CardOrder = 1
 	}
 
-	if err := s.DB.Save(&currCard).Error; err != nil {
-		return fmt.Errorf("failed to update card: %w", err)
-	}
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if prevListID != currCard.ListID {
+			if err := stagerules.Lock(tx, &list); err != nil {
+				return err
+			}
+			if err := stagerules.Check(tx, list, currCard, nil); err != nil {
+				return err
+			}
+		}
+		if err := tx.Save(&currCard).Error; err != nil {
+			return fmt.Errorf("failed to update card: %w", err)
+		}
+		if prevListID == currCard.ListID {
+			return nil
+		}
 
+		var lists []models.List
+		if err := tx.Unscoped().Where("id IN ?", []uint{prevListID, currCard.ListID}).Find(&lists).Error; err != nil {
+			return err
+		}
+		names := map[uint]string{}
+		for _, l := range lists {
+			names[l.ID] = l.Name
+		}
+		if err := analytics.RecordTransitions(tx, analytics.Transition(currCard.ID, prevListID, currCard.ListID, user.ID)); err != nil {
+			return err
+		}
+		return history.Record(tx, history.Change(currCard.ID, user.ID, models.CardHistoryList, "list", names[prevListID], names[currCard.ListID]))
+	})
+	if err != nil {
+		return err
+	}
+	if prevListID != currCard.ListID {
+		automation.Dispatch(automation.Event{Trigger: models.TriggerCardMoved, CardID: currCard.ID, UserID: user.ID, ListID: currCard.ListID})
+	}
 	return nil
 }
 
@@ -154,6 +240,110 @@ func (s *service) DeleteCard(ctx context.Context, req DeleteCardReq, user models
 	}, nil
 }
 
+// PurgeCards hard-deletes cards together with their field values, activities,
+// history, stage transitions and tag links.
+func PurgeCards(db *gorm.DB, cardIDs []uint) error {
+	if len(cardIDs) == 0 {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := fieldtype.UnlinkCards(tx, cardIDs...); err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("card_id IN ?", cardIDs).Delete(&models.FieldValue{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("card_id IN ?", cardIDs).Delete(&models.Activity{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("card_id IN ?", cardIDs).Delete(&models.CardHistory{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("card_id IN ?", cardIDs).Delete(&models.StageTransition{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM card_tags WHERE card_id IN ?", cardIDs).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id IN ?", cardIDs).Delete(&models.Card{}).Error
+	})
+}
+
+// findTrashedCard loads a soft-deleted card of the user. The card's list may
+// itself be in the trash.
+func (s *service) findTrashedCard(id uint, user models.User) (*models.Card, error) {
+	var card models.Card
+	s.DB.Unscoped().
+		Joins("JOIN lists ON lists.id = cards.list_id").
+		Where("cards.id = ? AND cards.deleted_at IS NOT NULL AND lists.user_id = ?", id, user.ID).
+		First(&card)
+	if card.ID == 0 {
+		logger.Logger.Error("card not found in trash", zap.String("card_id", strconv.Itoa(int(id))))
+		return nil, errors.New("card not found in trash")
+	}
+	return &card, nil
+}
+
+func (s *service) GetTrash(ctx context.Context, user models.User) (*GetTrashResp, error) {
+	cards := []TrashCard{}
+
+	if err := s.DB.Unscoped().
+		Model(&models.Card{}).
+		Select("cards.id, cards.name, cards.email, cards.image_url, cards.list_id, lists.name AS list_name, cards.deleted_at").
+		Joins("JOIN lists ON lists.id = cards.list_id").
+		Where("cards.deleted_at IS NOT NULL AND lists.user_id = ?", user.ID).
+		Order("cards.deleted_at DESC").
+		Scan(&cards).Error; err != nil {
+		return nil, err
+	}
+
+	return &GetTrashResp{Cards: cards}, nil
+}
+
+func (s *service) RestoreCard(ctx context.Context, req RestoreCardReq, user models.User) (*RestoreCardResp, error) {
+	card, err := s.findTrashedCard(req.ID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	var list models.List
+	s.DB.Where("id = ?", card.ListID).First(&list)
+	if list.ID == 0 {
+		return nil, errors.New("the card's list is deleted, restore the list first")
+	}
+
+	// A restored card enters its list again, so the list's entry rules apply.
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := stagerules.Lock(tx, &list); err != nil {
+			return err
+		}
+		if err := stagerules.Check(tx, list, *card, nil); err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(card).Update("deleted_at", nil).Error
+	})
+	if err != nil {
+		logger.Logger.Error("Error restoring card", zap.Error(err))
+		return nil, fmt.Errorf("failed to restore card: %w", err)
+	}
+
+	return &RestoreCardResp{card.ID}, nil
+}
+
+func (s *service) DeleteCardPermanently(ctx context.Context, req DeleteCardReq, user models.User) (*DeleteCardResp, error) {
+	card, err := s.findTrashedCard(req.ID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := PurgeCards(s.DB, []uint{card.ID}); err != nil {
+		logger.Logger.Error("Error purging card", zap.Error(err))
+		return nil, fmt.Errorf("failed to delete card: %w", err)
+	}
+
+	return &DeleteCardResp{card.ID}, nil
+}
+
 func (s *service) UpdateCard(ctx context.Context, req UpdateCardReq, user models.User) (*UpdateCardResp, error) {
 	var card models.Card
 	s.DB.Preload("List").Where("id = ?", req.ID).First(&card)
@@ -170,45 +360,248 @@ func (s *service) UpdateCard(ctx context.Context, req UpdateCardReq, user models
 		return nil, errors.New("card not found for user")
 	}
 
+	before := card
 	card.Name = req.Name
 	card.Designation = req.Designation
 	card.Email = req.Email
 	card.Phone = req.Phone
 	card.ImageURL = req.ImageURL
 
-	s.DB.Save(&card)
+	if err := s.saveWithHistory(&card, before, user.ID, nil); err != nil {
+		logger.Logger.Error("Error updating card", zap.Error(err))
+		return nil, fmt.Errorf("failed to update card: %w", err)
+	}
 	return &UpdateCardResp{card.ID}, nil
 }
 
+func validateProspect(p BulkProspect) []string {
+	var reasons []string
+	if strings.TrimSpace(p.Name) == "" && strings.TrimSpace(p.Email) == "" && strings.TrimSpace(p.ProfileURL) == "" {
+		reasons = append(reasons, "one of name, email or profile_url is required")
+	}
+	if email := strings.TrimSpace(p.Email); email != "" {
+		if _, err := mail.ParseAddress(email); err != nil {
+			reasons = append(reasons, "invalid email: "+email)
+		}
+	}
+	if profileURL := strings.TrimSpace(p.ProfileURL); profileURL != "" {
+		if u, err := url.Parse(profileURL); err != nil || u.Host == "" {
+			reasons = append(reasons, "invalid profile_url: "+profileURL)
+		}
+	}
+	return reasons
+}
+
+// applyProspect copies every non-empty prospect field onto the card and
+// reports whether anything changed.
+func applyProspect(card *models.Card, p BulkProspect) bool {
+	changed := false
+	set := func(dst *string, v string) {
+		if v != "" && *dst != v {
+			*dst = v
+			changed = true
+		}
+	}
+	set(&card.Name, p.Name)
+	set(&card.Designation, p.Designation)
+	set(&card.Email, p.Email)
+	set(&card.Phone, p.Phone)
+	set(&card.ImageURL, p.ImageURL)
+	set(&card.ProfileUrl, p.ProfileURL)
+	set(&card.AISummary, p.AISummary)
+	return changed
+}
+
 func (s *service) BulkCreate(ctx context.Context, req BulkCreateReq, key models.Key) (*BulkCreateResp, error) {
-	var cards []models.Card
 	var list models.List
 
-	s.DB.Where("id = ? AND user_id = ?", req.ListID, key.UserID).First(&list)
+	mode := req.Mode
+	if mode == "" {
+		mode = BulkModeInsert
+	}
+	if mode != BulkModeInsert && mode != BulkModeUpsert {
+		return nil, errors.New("invalid mode: " + string(req.Mode))
+	}
+
+	query := s.DB.Where("user_id = ?", key.UserID)
+	if req.BoardID != 0 {
+		_board, err := board.FindBoard(s.DB, req.BoardID, key.UserID)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("board_id = ?", _board.ID)
+	} else if req.ListID == 0 {
+		return nil, errors.New("list_id or board_id is required")
+	}
+	if req.ListID != 0 {
+		query = query.Where("id = ?", req.ListID)
+	}
+	query.Order("list_order ASC, id ASC").First(&list)
 	if list.ID == 0 {
 		logger.Logger.Error("list not found for list_id: ", zap.String("list_id", strconv.Itoa(int(req.ListID))))
 		return nil, errors.New("list not found for list_id: " + strconv.Itoa(int(req.ListID)))
 	}
 
+	// Existing cards of the user, indexed by normalized email and profile url.
+	byEmail := map[string]*models.Card{}
+	byProfile := map[string]*models.Card{}
+	if mode == BulkModeUpsert {
+		var existing []models.Card
+		if err := s.DB.
+			Joins("JOIN lists ON lists.id = cards.list_id AND lists.deleted_at IS NULL").
+			Where("lists.user_id = ? AND (cards.email <> '' OR cards.profile_url <> '')", key.UserID).
+			Find(&existing).Error; err != nil {
+			logger.Logger.Error("failed to load existing cards", zap.Error(err))
+			return nil, err
+		}
+		for i := range existing {
+			if k := util.NormalizeEmail(existing[i].Email); k != "" {
+				byEmail[k] = &existing[i]
+			}
+			if k := util.NormalizeProfileURL(existing[i].ProfileUrl); k != "" {
+				byProfile[k] = &existing[i]
+			}
+		}
+	}
+
+	fields, err := loadUserFields(s.DB, key.UserID)
+	if err != nil {
+		return nil, err
+	}
+
 	var maxOrder float64
 	s.DB.Model(&models.Card{}).Select("COALESCE(MAX(card_order), 0)").Scan(&maxOrder)
 
+	res := &BulkCreateResp{Results: make([]BulkRowResult, 0, len(req.Prospects))}
+	// Rows already handled in this request, so a repeated prospect is skipped
+	// instead of being written twice.
+	seenEmail := map[string]int{}
+	seenProfile := map[string]int{}
+
 	for i, prospect := range req.Prospects {
-		cards = append(cards, models.Card{
-			Name:        prospect.Name,
-			Designation: prospect.Designation,
-			Email:       prospect.Email,
-			Phone:       prospect.Phone,
-			ImageURL:    prospect.ImageURL,
-			ListID:      req.ListID,
-			CardOrder:   maxOrder + float64(i+1),
-			ProfileUrl:  prospect.ProfileURL,
-			AISummary:   prospect.AISummary,
-		})
+		result := BulkRowResult{Index: i}
+		emailKey := util.NormalizeEmail(prospect.Email)
+		profileKey := util.NormalizeProfileURL(prospect.ProfileURL)
+
+		if reasons := validateProspect(prospect); len(reasons) > 0 {
+			result.Status = BulkRowFailed
+			result.Reasons = reasons
+			res.Failed++
+			res.Results = append(res.Results, result)
+			continue
+		}
+
+		if j, ok := seenEmail[emailKey]; ok && emailKey != "" {
+			result.Status = BulkRowSkipped
+			result.CardID = res.Results[j].CardID
+			result.Reasons = []string{fmt.Sprintf("duplicate of row %d", j)}
+			res.Skipped++
+			res.Results = append(res.Results, result)
+			continue
+		}
+		if j, ok := seenProfile[profileKey]; ok && profileKey != "" {
+			result.Status = BulkRowSkipped
+			result.CardID = res.Results[j].CardID
+			result.Reasons = []string{fmt.Sprintf("duplicate of row %d", j)}
+			res.Skipped++
+			res.Results = append(res.Results, result)
+			continue
+		}
+
+		var match *models.Card
+		if mode == BulkModeUpsert {
+			if match = byEmail[emailKey]; match == nil {
+				match = byProfile[profileKey]
+			}
+		}
+
+		var existing map[uint]string
+		if match != nil {
+			existing, err = cardValues(s.DB, match.ID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		changes, err := fields.plan(prospect.Fields, existing)
+
+		if err != nil {
+			result.Status = BulkRowFailed
+			result.Reasons = []string{err.Error()}
+			res.Failed++
+		} else if match != nil {
+			result.CardID = match.ID
+			before := *match
+			if !applyProspect(match, prospect) && len(changes.values) == 0 {
+				result.Status = BulkRowSkipped
+				result.Reasons = []string{"no changes"}
+				res.Skipped++
+			} else if err := s.saveWithHistory(match, before, key.UserID, changes); err != nil {
+				logger.Logger.Error("failed to update prospect", zap.Int("index", i), zap.Error(err))
+				result.Status = BulkRowFailed
+				result.Reasons = []string{err.Error()}
+				res.Failed++
+			} else {
+				result.Status = BulkRowUpdated
+				res.Updated++
+				automation.Dispatch(changes.events(match.ID, key.UserID)...)
+			}
+		} else {
+			maxOrder++
+			card := models.Card{
+				Name:        prospect.Name,
+				Designation: prospect.Designation,
+				Email:       prospect.Email,
+				Phone:       prospect.Phone,
+				ImageURL:    prospect.ImageURL,
+				ListID:      list.ID,
+				CardOrder:   maxOrder,
+				ProfileUrl:  prospect.ProfileURL,
+				AISummary:   prospect.AISummary,
+			}
+			var ruleErr *stagerules.Error
+			if err := s.DB.Transaction(func(tx *gorm.DB) error {
+				if err := stagerules.Lock(tx, &list); err != nil {
+					return err
+				}
+				if err := stagerules.Check(tx, list, card, changes.values); err != nil {
+					return err
+				}
+				if err := tx.Create(&card).Error; err != nil {
+					return err
+				}
+				if err := changes.write(tx, card.ID, key.UserID); err != nil {
+					return err
+				}
+				return analytics.RecordTransitions(tx, analytics.Transition(card.ID, 0, list.ID, key.UserID))
+			}); errors.As(err, &ruleErr) {
+				result.Status = BulkRowFailed
+				result.Reasons = ruleErr.Reasons()
+				res.Failed++
+			} else if err != nil {
+				logger.Logger.Error("failed to create prospect", zap.Int("index", i), zap.Error(err))
+				result.Status = BulkRowFailed
+				result.Reasons = []string{err.Error()}
+				res.Failed++
+			} else {
+				result.Status = BulkRowCreated
+				result.CardID = card.ID
+				res.Created++
+				automation.Dispatch(automation.Event{Trigger: models.TriggerCardCreated, CardID: card.ID, UserID: key.UserID})
+			}
+		}
+
+		if result.Status != BulkRowFailed {
+			if emailKey != "" {
+				seenEmail[emailKey] = i
+			}
+			if profileKey != "" {
+				seenProfile[profileKey] = i
+			}
+		}
+		res.Results = append(res.Results, result)
 	}
-	s.DB.Create(&cards)
 
-	return nil, nil
+	return res, nil
 }
 
 func (s *service) GetCardByID(ctx context.Context, req GetCardByIDReq, user models.User) (*GetCardByIDResp, error) {
@@ -218,7 +611,6 @@ func (s *service) GetCardByID(ctx context.Context, req GetCardByIDReq, user mode
 	var additionalCompanyDetails []CompanyDetails
 	var cardActivity []models.Activity
 	var activity []GetCardActivity
-	var fieldDefIds []uint
 	var fieldDefs []models.FieldDefinition
 
 	s.DB.Preload("Tags").Preload("List").Where("id = ?", req.ID).First(&card)
@@ -236,45 +628,35 @@ func (s *service) GetCardByID(ctx context.Context, req GetCardByIDReq, user mode
 		})
 	}
 
-	s.DB.Preload("FieldDefinition").Where("card_id = ?", card.ID).Find(&fieldVals)
+	// Every active field of the user is listed, in the user's order, with
+	// the card's value when it has one.
+	s.DB.Where("card_id = ?", card.ID).Order("id ASC").Find(&fieldVals)
+	values := map[uint]string{}
 	for _, fieldVal := range fieldVals {
-		if models.FieldDefinitionType(fieldVal.FieldDefinition.Type) == models.CardTypeContact {
-			fieldDefIds = append(fieldDefIds, fieldVal.FieldDefinition.ID)
-			additionalContactDetails = append(additionalContactDetails, ContactDetails{
-				ID:       fieldVal.FieldDefinition.ID,
-				Name:     fieldVal.FieldDefinition.Name,
-				Value:    fieldVal.Value,
-				DataType: fieldVal.FieldDefinition.DataType,
-			})
-		} else if models.FieldDefinitionType(fieldVal.FieldDefinition.Type) == models.CardTypeCompany {
-			fieldDefIds = append(fieldDefIds, fieldVal.FieldDefinition.ID)
-			additionalCompanyDetails = append(additionalCompanyDetails, CompanyDetails{
-				ID:       fieldVal.FieldDefinition.ID,
-				Name:     fieldVal.FieldDefinition.Name,
-				Value:    fieldVal.Value,
-				DataType: fieldVal.FieldDefinition.DataType,
-			})
-		}
-	}
-	if len(fieldDefIds) == 0 {
-		s.DB.Where("user_id = ?", user.ID).Find(&fieldDefs)
-	} else {
-		s.DB.Where("id not in (?) AND user_id = ?", fieldDefIds, user.ID).Find(&fieldDefs)
+		values[fieldVal.FieldID] = fieldVal.Value
 	}
+	s.DB.Where("user_id = ? AND NOT archived", user.ID).Order("field_order ASC, id ASC").Find(&fieldDefs)
 	for _, fieldDef := range fieldDefs {
+		value := values[fieldDef.ID]
 		if models.FieldDefinitionType(fieldDef.Type) == models.CardTypeContact {
 			additionalContactDetails = append(additionalContactDetails, ContactDetails{
-				ID:       fieldDef.ID,
-				Name:     fieldDef.Name,
-				Value:    "",
-				DataType: fieldDef.DataType,
+				ID:         fieldDef.ID,
+				Name:       fieldDef.Name,
+				Value:      value,
+				DataType:   fieldDef.DataType,
+				TypedValue: fieldtype.Typed(fieldDef, value),
+				Currency:   fieldDef.Currency,
+				Required:   fieldDef.Required,
 			})
 		} else if models.FieldDefinitionType(fieldDef.Type) == models.CardTypeCompany {
 			additionalCompanyDetails = append(additionalCompanyDetails, CompanyDetails{
-				ID:       fieldDef.ID,
-				Name:     fieldDef.Name,
-				Value:    "",
-				DataType: fieldDef.DataType,
+				ID:         fieldDef.ID,
+				Name:       fieldDef.Name,
+				Value:      value,
+				DataType:   fieldDef.DataType,
+				TypedValue: fieldtype.Typed(fieldDef, value),
+				Currency:   fieldDef.Currency,
+				Required:   fieldDef.Required,
 			})
 		}
 	}
@@ -331,6 +713,7 @@ func (s *service) UpdateCardByID(ctx context.Context, req UpdateCardByIDReq, use
 		return nil, errors.New("card_id not found for card_id: " + strconv.Itoa(int(req.ID)))
 	}
 
+	before := card
 	card.Name = req.Name
 	card.Designation = req.Designation
 	card.Email = req.Email
@@ -343,7 +726,675 @@ func (s *service) UpdateCardByID(ctx context.Context, req UpdateCardByIDReq, use
 	card.CompanyPhone = req.CompanyPhone
 	card.CompanyEmail = req.CompanyEmail
 
-	s.DB.Save(&card)
+	fields, err := loadUserFields(s.DB, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := cardValues(s.DB, card.ID)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := fields.plan(req.Fields, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.saveWithHistory(&card, before, user.ID, changes); err != nil {
+		logger.Logger.Error("Error updating card", zap.Error(err))
+		return nil, fmt.Errorf("failed to update card: %w", err)
+	}
+	automation.Dispatch(changes.events(card.ID, user.ID)...)
 
 	return &UpdateCardByIDResp{card.ID}, nil
 }
+
+// diffCard returns one history entry per built-in field that differs between
+// before and after, in a stable order.
+func diffCard(before, after models.Card, actorID uint) []models.CardHistory {
+	fields := make([]string, 0, len(models.CardFields))
+	for field := range models.CardFields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var changes []models.CardHistory
+	for _, field := range fields {
+		get := models.CardFields[field]
+		if oldValue, newValue := *get(&before), *get(&after); oldValue != newValue {
+			changes = append(changes, history.Change(after.ID, actorID, models.CardHistoryField, field, oldValue, newValue))
+		}
+	}
+	return changes
+}
+
+// saveWithHistory saves the card and its custom field changes, if any, and
+// records what changed since before.
+func (s *service) saveWithHistory(card *models.Card, before models.Card, actorID uint, changes *fieldChanges) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(card).Error; err != nil {
+			return err
+		}
+		if changes != nil {
+			if err := changes.write(tx, card.ID, actorID); err != nil {
+				return err
+			}
+		}
+		return history.Record(tx, diffCard(before, *card, actorID)...)
+	})
+}
+
+func (s *service) FindDuplicates(ctx context.Context, user models.User) (*FindDuplicatesResp, error) {
+	var cards []models.Card
+
+	if err := s.DB.
+		Select("cards.id, cards.name, cards.email, cards.phone, cards.profile_url, cards.company_name, cards.list_id, cards.created_at").
+		Joins("JOIN lists ON lists.id = cards.list_id AND lists.deleted_at IS NULL").
+		Where("lists.user_id = ?", user.ID).
+		Find(&cards).Error; err != nil {
+		logger.Logger.Error("failed to load cards for duplicate detection", zap.Error(err))
+		return nil, err
+	}
+
+	return &FindDuplicatesResp{Groups: findDuplicateGroups(cards)}, nil
+}
+
+func (s *service) MergeCards(ctx context.Context, req MergeCardReq, user models.User) (*MergeCardResp, error) {
+	ids := []uint{req.SurvivorID}
+	seen := map[uint]bool{req.SurvivorID: true}
+	for _, id := range req.MergeIDs {
+		if seen[id] {
+			return nil, fmt.Errorf("card %d is listed more than once", id)
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if len(ids) < 2 {
+		return nil, errors.New("at least one card to merge is required")
+	}
+	for field, from := range req.Fields {
+		if _, ok := models.CardFields[field]; !ok {
+			return nil, fmt.Errorf("field %s cannot be merged", field)
+		}
+		if !seen[from] {
+			return nil, fmt.Errorf("card %d chosen for %s is not part of the merge", from, field)
+		}
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var cards []models.Card
+		if err := tx.
+			Joins("JOIN lists ON lists.id = cards.list_id AND lists.deleted_at IS NULL").
+			Where("cards.id IN ? AND lists.user_id = ?", ids, user.ID).
+			Find(&cards).Error; err != nil {
+			return err
+		}
+		if len(cards) != len(ids) {
+			return errors.New("card not found for user")
+		}
+
+		byID := map[uint]*models.Card{}
+		for i := range cards {
+			byID[cards[i].ID] = &cards[i]
+		}
+		survivor := byID[req.SurvivorID]
+		before := *survivor
+
+		for field, get := range models.CardFields {
+			if from, ok := req.Fields[field]; ok {
+				*get(survivor) = *get(byID[from])
+				continue
+			}
+			if strings.TrimSpace(*get(survivor)) != "" {
+				continue
+			}
+			for _, id := range req.MergeIDs {
+				if v := *get(byID[id]); strings.TrimSpace(v) != "" {
+					*get(survivor) = v
+					break
+				}
+			}
+		}
+		if err := tx.Save(survivor).Error; err != nil {
+			return fmt.Errorf("failed to update surviving card: %w", err)
+		}
+		if err := history.Record(tx, diffCard(before, *survivor, user.ID)...); err != nil {
+			return err
+		}
+
+		var survivorVals, mergedVals []models.FieldValue
+		if err := tx.Where("card_id = ?", survivor.ID).Find(&survivorVals).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("card_id IN ?", req.MergeIDs).Order("id ASC").Find(&mergedVals).Error; err != nil {
+			return err
+		}
+		byField := map[uint]*models.FieldValue{}
+		for i := range survivorVals {
+			byField[survivorVals[i].FieldID] = &survivorVals[i]
+		}
+		for i := range mergedVals {
+			val := &mergedVals[i]
+			existing, ok := byField[val.FieldID]
+			if !ok {
+				if err := tx.Model(val).Update("card_id", survivor.ID).Error; err != nil {
+					return err
+				}
+				byField[val.FieldID] = val
+				continue
+			}
+			if existing.Value == "" && val.Value != "" {
+				existing.Value = val.Value
+				if err := tx.Save(existing).Error; err != nil {
+					return err
+				}
+			}
+			if err := tx.Delete(val).Error; err != nil {
+				return err
+			}
+		}
+		// Values moved to the survivor are relinked below; the deleted ones
+		// must not count towards option usage and filters anymore.
+		if err := fieldtype.UnlinkCards(tx, req.MergeIDs...); err != nil {
+			return err
+		}
+		if err := fieldtype.RelinkCards(tx, survivor.ID); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Activity{}).
+			Where("card_id IN ?", req.MergeIDs).
+			Update("card_id", survivor.ID).Error; err != nil {
+			return fmt.Errorf("failed to move activities: %w", err)
+		}
+
+		if err := tx.Exec(
+			"INSERT INTO card_tags (card_id, tag_id) SELECT DISTINCT ?::bigint, tag_id FROM card_tags WHERE card_id IN ? ON CONFLICT DO NOTHING",
+			survivor.ID, req.MergeIDs,
+		).Error; err != nil {
+			return fmt.Errorf("failed to move tags: %w", err)
+		}
+		if err := tx.Exec("DELETE FROM card_tags WHERE card_id IN ?", req.MergeIDs).Error; err != nil {
+			return fmt.Errorf("failed to move tags: %w", err)
+		}
+
+		return tx.Delete(&models.Card{}, req.MergeIDs).Error
+	})
+	if err != nil {
+		logger.Logger.Error("failed to merge cards", zap.Uint("survivor_id", req.SurvivorID), zap.Error(err))
+		return nil, err
+	}
+
+	return &MergeCardResp{
+		ID:        req.SurvivorID,
+		MergedIDs: req.MergeIDs,
+	}, nil
+}
+
+// maxBulkCards caps the number of cards a single bulk operation may touch.
+const maxBulkCards = 1000
+
+// bulkCardIDs resolves the cards a bulk request targets, either the given ids
+// or every card of the user matching the filter.
+func (s *service) bulkCardIDs(req BulkCardReq, user models.User) ([]uint, error) {
+	if len(req.CardIDs) > 0 && req.Filter != nil {
+		return nil, errors.New("pass either card_ids or filter, not both")
+	}
+
+	if req.Filter == nil {
+		if len(req.CardIDs) == 0 {
+			return nil, errors.New("card_ids or filter is required")
+		}
+		var ids []uint
+		seen := map[uint]bool{}
+		for _, id := range req.CardIDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) > maxBulkCards {
+			return nil, fmt.Errorf("at most %d cards can be changed at once", maxBulkCards)
+		}
+		return ids, nil
+	}
+
+	query := s.DB.Model(&models.Card{}).
+		Joins("JOIN lists ON lists.id = cards.list_id AND lists.deleted_at IS NULL").
+		Where("lists.user_id = ?", user.ID)
+	if req.Filter.ListID != 0 {
+		query = query.Where("cards.list_id = ?", req.Filter.ListID)
+	}
+	if len(req.Filter.TagIDs) > 0 {
+		query = query.Where("cards.id IN (SELECT card_id FROM card_tags WHERE tag_id IN ?)", req.Filter.TagIDs)
+	}
+
+	var ids []uint
+	if err := query.Order("cards.id ASC").Limit(maxBulkCards+1).Pluck("cards.id", &ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) > maxBulkCards {
+		return nil, fmt.Errorf("filter matches more than %d cards", maxBulkCards)
+	}
+	return ids, nil
+}
+
+// moveToList puts card at order in list and returns the history entry and
+// stage transition of the move. The update goes through a bare model: with
+// card itself, gorm would take list_id from the preloaded List.
+func moveToList(tx *gorm.DB, card *models.Card, list models.List, order float64, actorID uint) (models.CardHistory, models.StageTransition, error) {
+	fromListID, fromName := card.ListID, card.List.Name
+	if err := tx.Model(&models.Card{}).Where("id = ?", card.ID).
+		Updates(map[string]interface{}{"list_id": list.ID, "card_order": order}).Error; err != nil {
+		return models.CardHistory{}, models.StageTransition{}, fmt.Errorf("failed to move card %d: %w", card.ID, err)
+	}
+	card.ListID, card.CardOrder, card.List = list.ID, order, list
+	return history.Change(card.ID, actorID, models.CardHistoryList, "list", fromName, list.Name),
+		analytics.Transition(card.ID, fromListID, list.ID, actorID), nil
+}
+
+func (s *service) BulkUpdate(ctx context.Context, req BulkCardReq, user models.User) (*BulkCardResp, error) {
+	ids, err := s.bulkCardIDs(req, user)
+	if err != nil {
+		return nil, err
+	}
+
+	var list models.List
+	var fieldDef models.FieldDefinition
+	tagsByID := map[uint]models.Tag{}
+
+	switch req.Operation {
+	case BulkOpMove:
+		s.DB.Where("id = ? AND user_id = ?", req.ListID, user.ID).First(&list)
+		if list.ID == 0 {
+			logger.Logger.Error("list not found", zap.String("list_id", strconv.Itoa(int(req.ListID))))
+			return nil, errors.New("list not found")
+		}
+	case BulkOpAddTags, BulkOpRemoveTags:
+		if len(req.TagIDs) == 0 {
+			return nil, errors.New("tag_ids is required")
+		}
+		var tags []models.Tag
+		if err := s.DB.Where("id IN ? AND user_id = ?", req.TagIDs, user.ID).Find(&tags).Error; err != nil {
+			return nil, err
+		}
+		for _, t := range tags {
+			tagsByID[t.ID] = t
+		}
+		for _, id := range req.TagIDs {
+			if _, ok := tagsByID[id]; !ok {
+				return nil, fmt.Errorf("tag %d not found", id)
+			}
+		}
+	case BulkOpSetField:
+		fieldtype.WithOptions(s.DB).Where("id = ? AND user_id = ?", req.FieldID, user.ID).First(&fieldDef)
+		if fieldDef.ID == 0 {
+			return nil, errors.New("field definition does not exist")
+		}
+		if fieldDef.Archived {
+			return nil, fmt.Errorf("field %q is archived", fieldDef.Name)
+		}
+		value, err := fieldtype.Normalize(fieldDef, req.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fieldDef.Name, err)
+		}
+		if value == "" && fieldDef.Required {
+			return nil, fmt.Errorf("%s is required", fieldDef.Name)
+		}
+		req.Value = value
+	case BulkOpDelete:
+	default:
+		return nil, errors.New("invalid operation: " + string(req.Operation))
+	}
+
+	res := &BulkCardResp{Operation: req.Operation, Results: make([]BulkCardResult, 0, len(ids))}
+	// Automation events, dispatched once the transaction commits.
+	var events []automation.Event
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		var cards []models.Card
+		if len(ids) > 0 {
+			if err := tx.
+				Preload("List").
+				Joins("JOIN lists ON lists.id = cards.list_id AND lists.deleted_at IS NULL").
+				Where("cards.id IN ? AND lists.user_id = ?", ids, user.ID).
+				Find(&cards).Error; err != nil {
+				return err
+			}
+		}
+		byID := map[uint]*models.Card{}
+		for i := range cards {
+			byID[cards[i].ID] = &cards[i]
+		}
+
+		var maxOrder float64
+		if req.Operation == BulkOpMove {
+			if err := stagerules.Lock(tx, &list); err != nil {
+				return err
+			}
+			tx.Model(&models.Card{}).Select("COALESCE(MAX(card_order), 0)").Scan(&maxOrder)
+		}
+
+		var changes []models.CardHistory
+		var transitions []models.StageTransition
+		var deleteIDs []uint
+		for _, id := range ids {
+			card, ok := byID[id]
+			if !ok {
+				res.Results = append(res.Results, BulkCardResult{CardID: id, Status: BulkCardNotFound})
+				res.NotFound++
+				continue
+			}
+
+			changed := false
+			switch req.Operation {
+			case BulkOpMove:
+				if card.ListID != list.ID {
+					err := stagerules.Check(tx, list, *card, nil)
+					var ruleErr *stagerules.Error
+					if errors.As(err, &ruleErr) {
+						res.Results = append(res.Results, BulkCardResult{CardID: id, Status: BulkCardRejected, Reasons: ruleErr.Reasons()})
+						res.Rejected++
+						continue
+					}
+					if err != nil {
+						return err
+					}
+					maxOrder++
+					change, transition, err := moveToList(tx, card, list, maxOrder, user.ID)
+					if err != nil {
+						return err
+					}
+					changes = append(changes, change)
+					transitions = append(transitions, transition)
+					events = append(events, automation.Event{Trigger: models.TriggerCardMoved, CardID: card.ID, UserID: user.ID, ListID: list.ID})
+					changed = true
+				}
+			case BulkOpAddTags:
+				for _, tagID := range req.TagIDs {
+					result := tx.Exec("INSERT INTO card_tags (card_id, tag_id) VALUES (?, ?) ON CONFLICT DO NOTHING", card.ID, tagID)
+					if result.Error != nil {
+						return fmt.Errorf("failed to tag card %d: %w", card.ID, result.Error)
+					}
+					if result.RowsAffected > 0 {
+						changes = append(changes, history.Change(card.ID, user.ID, models.CardHistoryTagAdded, "tags", "", tagsByID[tagID].Name))
+						events = append(events, automation.Event{Trigger: models.TriggerTagAdded, CardID: card.ID, UserID: user.ID, TagID: tagID})
+						changed = true
+					}
+				}
+			case BulkOpRemoveTags:
+				for _, tagID := range req.TagIDs {
+					result := tx.Exec("DELETE FROM card_tags WHERE card_id = ? AND tag_id = ?", card.ID, tagID)
+					if result.Error != nil {
+						return fmt.Errorf("failed to untag card %d: %w", card.ID, result.Error)
+					}
+					if result.RowsAffected > 0 {
+						changes = append(changes, history.Change(card.ID, user.ID, models.CardHistoryTagRemoved, "tags", tagsByID[tagID].Name, ""))
+						changed = true
+					}
+				}
+			case BulkOpSetField:
+				var fieldVal models.FieldValue
+				tx.Where("field_id = ? AND card_id = ?", fieldDef.ID, card.ID).First(&fieldVal)
+				if fieldVal.ID != 0 && fieldVal.Value == req.Value {
+					break
+				}
+				oldValue := fieldVal.Value
+				fieldVal.CardID = card.ID
+				fieldVal.FieldID = fieldDef.ID
+				fieldVal.Value = req.Value
+				if err := tx.Save(&fieldVal).Error; err != nil {
+					return fmt.Errorf("failed to set field on card %d: %w", card.ID, err)
+				}
+				if err := fieldtype.RelinkValues(tx, fieldDef, fieldVal.ID); err != nil {
+					return err
+				}
+				change := history.Change(card.ID, user.ID, models.CardHistoryCustomField, fieldDef.Name, oldValue, req.Value)
+				change.FieldID = fieldDef.ID
+				changes = append(changes, change)
+				events = append(events, automation.Event{Trigger: models.TriggerFieldChanged, CardID: card.ID, UserID: user.ID, FieldID: fieldDef.ID})
+				changed = true
+			case BulkOpDelete:
+				deleteIDs = append(deleteIDs, card.ID)
+				changed = true
+			}
+
+			if changed {
+				res.Results = append(res.Results, BulkCardResult{CardID: id, Status: BulkCardUpdated})
+				res.Updated++
+			} else {
+				res.Results = append(res.Results, BulkCardResult{CardID: id, Status: BulkCardUnchanged})
+				res.Unchanged++
+			}
+		}
+
+		if len(deleteIDs) > 0 {
+			if err := tx.Delete(&models.Card{}, deleteIDs).Error; err != nil {
+				return fmt.Errorf("failed to delete cards: %w", err)
+			}
+		}
+		if err := analytics.RecordTransitions(tx, transitions...); err != nil {
+			return err
+		}
+		return history.Record(tx, changes...)
+	})
+	if err != nil {
+		logger.Logger.Error("bulk card operation failed", zap.String("operation", string(req.Operation)), zap.Error(err))
+		return nil, err
+	}
+	automation.Dispatch(events...)
+
+	return res, nil
+}
+
+func vcardFileName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '_':
+			b.WriteRune('-')
+		}
+	}
+	if b.Len() == 0 {
+		return "contacts.vcf"
+	}
+	return b.String() + ".vcf"
+}
+
+func (s *service) ExportVCard(ctx context.Context, req ExportVCardReq, user models.User) (*ExportVCardResp, error) {
+	version := req.Version
+	if version == "" {
+		version = VCardVersion3
+	}
+	if version != VCardVersion3 && version != VCardVersion4 {
+		return nil, errors.New("version must be 3.0 or 4.0")
+	}
+
+	var cards []models.Card
+	var fileName string
+
+	if req.CardID != 0 {
+		var card models.Card
+		s.DB.Preload("List").Where("id = ?", req.CardID).First(&card)
+		if card.ID == 0 || card.List.UserID != user.ID {
+			logger.Logger.Error("card_id not found for card_id: ", zap.String("card_id", strconv.Itoa(int(req.CardID))))
+			return nil, errors.New("card_id not found for card_id: " + strconv.Itoa(int(req.CardID)))
+		}
+		cards = append(cards, card)
+		fileName = vcardFileName(card.Name)
+	} else {
+		var list models.List
+		s.DB.Where("id = ? AND user_id = ?", req.ListID, user.ID).First(&list)
+		if list.ID == 0 {
+			logger.Logger.Error("list not found for list_id: ", zap.String("list_id", strconv.Itoa(int(req.ListID))))
+			return nil, errors.New("list not found for list_id: " + strconv.Itoa(int(req.ListID)))
+		}
+		if err := s.DB.Where("list_id = ?", list.ID).Order("card_order ASC").Find(&cards).Error; err != nil {
+			return nil, err
+		}
+		fileName = vcardFileName(list.Name)
+	}
+
+	cardIDs := make([]uint, len(cards))
+	for i, c := range cards {
+		cardIDs[i] = c.ID
+	}
+	var fieldVals []models.FieldValue
+	if len(cardIDs) > 0 {
+		if err := s.DB.Preload("FieldDefinition").Where("card_id IN ?", cardIDs).Find(&fieldVals).Error; err != nil {
+			return nil, err
+		}
+	}
+	fieldsByCard := map[uint][]vcardField{}
+	for _, v := range fieldVals {
+		if v.FieldDefinition.ID == 0 || v.Value == "" {
+			continue
+		}
+		fieldsByCard[v.CardID] = append(fieldsByCard[v.CardID], vcardField{
+			Name:  v.FieldDefinition.Name,
+			Type:  v.FieldDefinition.Type,
+			Value: v.Value,
+		})
+	}
+
+	var buf bytes.Buffer
+	for _, c := range cards {
+		if err := writeVCard(&buf, c, fieldsByCard[c.ID], version); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ExportVCardResp{
+		FileName: fileName,
+		Content:  buf.Bytes(),
+	}, nil
+}
+
+func (s *service) ImportVCard(ctx context.Context, req ImportVCardReq, user models.User) (*ImportVCardResp, error) {
+	var list models.List
+	s.DB.Where("id = ? AND user_id = ?", req.ListID, user.ID).First(&list)
+	if list.ID == 0 {
+		logger.Logger.Error("list not found for list_id: ", zap.String("list_id", strconv.Itoa(int(req.ListID))))
+		return nil, errors.New("list not found for list_id: " + strconv.Itoa(int(req.ListID)))
+	}
+
+	vcards, err := parseVCards(bytes.NewReader(req.Content))
+	if err != nil {
+		return nil, err
+	}
+	if len(vcards) == 0 {
+		return nil, errors.New("no vcards found in file")
+	}
+
+	res := &ImportVCardResp{CardIDs: []uint{}, InvalidValues: []string{}, Errors: []string{}}
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		var fieldDefs []models.FieldDefinition
+		if err := fieldtype.WithOptions(tx).Where("user_id = ?", user.ID).Find(&fieldDefs).Error; err != nil {
+			return err
+		}
+		defByKey := map[string]*models.FieldDefinition{}
+		for i := range fieldDefs {
+			defByKey[fieldDefs[i].Type+":"+strings.ToLower(fieldDefs[i].Name)] = &fieldDefs[i]
+		}
+
+		if err := stagerules.Lock(tx, &list); err != nil {
+			return err
+		}
+
+		var maxOrder float64
+		tx.Model(&models.Card{}).Select("COALESCE(MAX(card_order), 0)").Scan(&maxOrder)
+
+		for _, props := range vcards {
+			card, fields := cardFromVCard(props)
+			if card.Name == "" && card.Email == "" {
+				res.Skipped++
+				continue
+			}
+
+			var fieldVals []models.FieldValue
+			var defs []*models.FieldDefinition
+			values := map[uint]string{}
+			for _, f := range fields {
+				fieldType := f.Type
+				if !models.FieldDefinitionType(fieldType).IsFieldTypeValid() {
+					fieldType = string(models.CardTypeContact)
+				}
+				key := fieldType + ":" + strings.ToLower(f.Name)
+				def, ok := defByKey[key]
+				if !ok {
+					def = &models.FieldDefinition{Name: f.Name, UserID: user.ID, Type: fieldType}
+					if err := tx.Create(def).Error; err != nil {
+						return fmt.Errorf("failed to create field %q: %w", f.Name, err)
+					}
+					defByKey[key] = def
+				}
+				if def.Archived {
+					res.InvalidValues = append(res.InvalidValues, fmt.Sprintf("%s: field %q is archived", card.Name, def.Name))
+					continue
+				}
+				value, err := fieldtype.Normalize(*def, f.Value)
+				if err != nil {
+					res.InvalidValues = append(res.InvalidValues, fmt.Sprintf("%s: %s: %s", card.Name, def.Name, err))
+					continue
+				}
+				fieldVals = append(fieldVals, models.FieldValue{FieldID: def.ID, Value: value})
+				defs = append(defs, def)
+				values[def.ID] = value
+			}
+
+			// The list's entry rules see the card with its defaults, which
+			// FillDefaults adds below.
+			values, err := fieldtype.WithDefaults(tx, user.ID, values)
+			if err != nil {
+				return err
+			}
+			err = stagerules.Check(tx, list, card, values)
+			var ruleErr *stagerules.Error
+			if errors.As(err, &ruleErr) {
+				res.Failed++
+				res.Errors = append(res.Errors, fmt.Sprintf("%s: %s", card.Name, strings.Join(ruleErr.Reasons(), ", ")))
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			maxOrder++
+			card.ListID = list.ID
+			card.CardOrder = maxOrder
+			if err := tx.Create(&card).Error; err != nil {
+				return fmt.Errorf("failed to create card %q: %w", card.Name, err)
+			}
+			if err := analytics.RecordTransitions(tx, analytics.Transition(card.ID, 0, list.ID, user.ID)); err != nil {
+				return err
+			}
+
+			for i := range fieldVals {
+				fieldVals[i].CardID = card.ID
+				if err := tx.Create(&fieldVals[i]).Error; err != nil {
+					return err
+				}
+				if err := fieldtype.RelinkValues(tx, *defs[i], fieldVals[i].ID); err != nil {
+					return err
+				}
+			}
+
+			res.Created++
+			res.CardIDs = append(res.CardIDs, card.ID)
+		}
+		return fieldtype.FillDefaults(tx, res.CardIDs...)
+	})
+	if err != nil {
+		logger.Logger.Error("failed to import vcards", zap.Error(err))
+		return nil, err
+	}
+
+	var events []automation.Event
+	for _, id := range res.CardIDs {
+		events = append(events, automation.Event{Trigger: models.TriggerCardCreated, CardID: id, UserID: user.ID})
+	}
+	automation.Dispatch(events...)
+
+	return res, nil
+}
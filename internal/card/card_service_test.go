@@ -0,0 +1,51 @@
+package card
+
+import (
+	"testing"
+
+	"github.com/Cognize-AI/client-cognize/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRunDB builds statements without a database; reads find nothing.
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestMoveToList(t *testing.T) {
+	card := &models.Card{ListID: 3, List: models.List{Name: "Lead"}}
+	card.ID = 7
+	card.List.ID = 3
+	list := models.List{Name: "Qualified"}
+	list.ID = 5
+
+	db := dryRunDB(t)
+	var vars []interface{}
+	db.Callback().Update().After("gorm:update").Register("test:vars", func(tx *gorm.DB) {
+		vars = tx.Statement.Vars
+	})
+
+	change, transition, err := moveToList(db, card, list, 4, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transition.CardID != 7 || transition.FromListID != 3 || transition.ToListID != 5 || transition.UserID != 2 {
+		t.Errorf("transition = %+v, want card 7 from list 3 to list 5 by user 2", transition)
+	}
+	if change.OldValue != "Lead" || change.NewValue != "Qualified" {
+		t.Errorf("history change = %q -> %q, want Lead -> Qualified", change.OldValue, change.NewValue)
+	}
+	// UPDATE cards SET card_order, list_id, updated_at WHERE id
+	if len(vars) != 4 || vars[0] != float64(4) || vars[1] != uint(5) {
+		t.Errorf("update vars = %v, want card_order 4 and list_id 5", vars)
+	}
+	if card.ListID != 5 || card.CardOrder != 4 || card.List.ID != 5 {
+		t.Errorf("card list %d order %v, want list 5 order 4", card.ListID, card.CardOrder)
+	}
+}
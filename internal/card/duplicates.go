@@ -0,0 +1,197 @@
+package card
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Cognize-AI/client-cognize/models"
+	"github.com/Cognize-AI/client-cognize/util"
+)
+
+// nameSimilarityThreshold is the minimum normalized Levenshtein similarity
+// for two names at the same company to be considered the same person.
+const nameSimilarityThreshold = 0.85
+
+// legalSuffixes are dropped from the end of company names, so "Zeta Inc" and
+// "Zeta" compare equal.
+var legalSuffixes = map[string]bool{"inc": true, "llc": true, "ltd": true, "gmbh": true, "corp": true, "pvt": true}
+
+// nameTokens lowercases a name and splits it into words, dropping punctuation.
+func nameTokens(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// normalizeName lowercases, strips punctuation and sorts the tokens so that
+// "Doe, John" and "john doe" normalize to the same value.
+func normalizeName(name string) string {
+	fields := nameTokens(name)
+	sort.Strings(fields)
+	return strings.Join(fields, " ")
+}
+
+// normalizeCompany is normalizeName after dropping trailing legal suffixes,
+// which must happen before the tokens are sorted.
+func normalizeCompany(company string) string {
+	fields := nameTokens(company)
+	for len(fields) > 1 && legalSuffixes[fields[len(fields)-1]] {
+		fields = fields[:len(fields)-1]
+	}
+	sort.Strings(fields)
+	return strings.Join(fields, " ")
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func nameSimilarity(a, b string) float64 {
+	longest := max(len([]rune(a)), len([]rune(b)))
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(levenshtein(a, b))/float64(longest)
+}
+
+// duplicateFinder groups cards with a union-find, remembering every rule that
+// linked two cards together.
+type duplicateFinder struct {
+	parent  map[uint]uint
+	reasons map[uint]map[DuplicateReason]bool
+}
+
+func newDuplicateFinder() *duplicateFinder {
+	return &duplicateFinder{
+		parent:  map[uint]uint{},
+		reasons: map[uint]map[DuplicateReason]bool{},
+	}
+}
+
+func (f *duplicateFinder) find(id uint) uint {
+	if _, ok := f.parent[id]; !ok {
+		f.parent[id] = id
+	}
+	for f.parent[id] != id {
+		f.parent[id] = f.parent[f.parent[id]]
+		id = f.parent[id]
+	}
+	return id
+}
+
+func (f *duplicateFinder) union(a, b uint, reason DuplicateReason) {
+	ra, rb := f.find(a), f.find(b)
+	if ra != rb {
+		f.parent[rb] = ra
+		for r := range f.reasons[rb] {
+			f.addReason(ra, r)
+		}
+		delete(f.reasons, rb)
+	}
+	f.addReason(ra, reason)
+}
+
+func (f *duplicateFinder) addReason(root uint, reason DuplicateReason) {
+	if f.reasons[root] == nil {
+		f.reasons[root] = map[DuplicateReason]bool{}
+	}
+	f.reasons[root][reason] = true
+}
+
+// linkByKey unions every card sharing the same non-empty key.
+func (f *duplicateFinder) linkByKey(cards []models.Card, reason DuplicateReason, key func(models.Card) string) {
+	seen := map[string]uint{}
+	for _, c := range cards {
+		k := key(c)
+		if k == "" {
+			continue
+		}
+		if first, ok := seen[k]; ok {
+			f.union(first, c.ID, reason)
+		} else {
+			seen[k] = c.ID
+		}
+	}
+}
+
+// linkByNameAndCompany compares names pairwise within each normalized company.
+func (f *duplicateFinder) linkByNameAndCompany(cards []models.Card) {
+	byCompany := map[string][]models.Card{}
+	for _, c := range cards {
+		company := normalizeCompany(c.CompanyName)
+		if company == "" || normalizeName(c.Name) == "" {
+			continue
+		}
+		byCompany[company] = append(byCompany[company], c)
+	}
+	for _, group := range byCompany {
+		for i := 0; i < len(group); i++ {
+			for j := i + 1; j < len(group); j++ {
+				if nameSimilarity(normalizeName(group[i].Name), normalizeName(group[j].Name)) >= nameSimilarityThreshold {
+					f.union(group[i].ID, group[j].ID, DuplicateReasonNameCompany)
+				}
+			}
+		}
+	}
+}
+
+func findDuplicateGroups(cards []models.Card) []DuplicateGroup {
+	f := newDuplicateFinder()
+	f.linkByKey(cards, DuplicateReasonEmail, func(c models.Card) string { return util.NormalizeEmail(c.Email) })
+	f.linkByKey(cards, DuplicateReasonPhone, func(c models.Card) string { return util.NormalizePhone(c.Phone) })
+	f.linkByKey(cards, DuplicateReasonProfileURL, func(c models.Card) string { return util.NormalizeProfileURL(c.ProfileUrl) })
+	f.linkByNameAndCompany(cards)
+
+	members := map[uint][]DuplicateCard{}
+	for _, c := range cards {
+		if _, ok := f.parent[c.ID]; !ok {
+			continue
+		}
+		root := f.find(c.ID)
+		members[root] = append(members[root], DuplicateCard{
+			ID:          c.ID,
+			Name:        c.Name,
+			Email:       c.Email,
+			Phone:       c.Phone,
+			ProfileURL:  c.ProfileUrl,
+			CompanyName: c.CompanyName,
+			ListID:      c.ListID,
+			CreatedAt:   c.CreatedAt,
+		})
+	}
+
+	var groups []DuplicateGroup
+	for root, cards := range members {
+		if len(cards) < 2 {
+			continue
+		}
+		var reasons []DuplicateReason
+		for r := range f.reasons[root] {
+			reasons = append(reasons, r)
+		}
+		sort.Slice(reasons, func(i, j int) bool { return reasons[i] < reasons[j] })
+		sort.Slice(cards, func(i, j int) bool { return cards[i].ID < cards[j].ID })
+		groups = append(groups, DuplicateGroup{Reasons: reasons, Cards: cards})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Cards[0].ID < groups[j].Cards[0].ID })
+
+	return groups
+}
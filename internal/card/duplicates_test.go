@@ -0,0 +1,24 @@
+package card
+
+import "testing"
+
+func TestNormalizeCompany(t *testing.T) {
+	tests := []struct {
+		company string
+		want    string
+	}{
+		{"Acme Inc", "acme"},
+		{"Zeta Inc", "zeta"},
+		{"Zeta", "zeta"},
+		{"Zeta, Inc.", "zeta"},
+		{"Widgets Pvt. Ltd.", "widgets"},
+		{"Blue Ocean LLC", "blue ocean"},
+		{"Inc", "inc"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeCompany(tt.company); got != tt.want {
+			t.Errorf("normalizeCompany(%q) = %q, want %q", tt.company, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package card
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Cognize-AI/client-cognize/internal/automation"
+	"github.com/Cognize-AI/client-cognize/internal/fieldtype"
+	"github.com/Cognize-AI/client-cognize/internal/history"
+	"github.com/Cognize-AI/client-cognize/models"
+	"gorm.io/gorm"
+)
+
+// userFields are the user's active custom fields, in display order, with
+// their options.
+type userFields []models.FieldDefinition
+
+func loadUserFields(db *gorm.DB, userID uint) (userFields, error) {
+	var defs []models.FieldDefinition
+	if err := fieldtype.WithOptions(db).
+		Where("user_id = ? AND NOT archived", userID).
+		Order("field_order ASC, id ASC").
+		Find(&defs).Error; err != nil {
+		return nil, err
+	}
+	return defs, nil
+}
+
+func (f userFields) find(id uint) (models.FieldDefinition, bool) {
+	for _, def := range f {
+		if def.ID == id {
+			return def, true
+		}
+	}
+	return models.FieldDefinition{}, false
+}
+
+// cardValues returns the card's custom field values by field id.
+func cardValues(db *gorm.DB, cardID uint) (map[uint]string, error) {
+	var rows []models.FieldValue
+	if err := db.Where("card_id = ?", cardID).Order("id ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	values := map[uint]string{}
+	for _, row := range rows {
+		values[row.FieldID] = row.Value
+	}
+	return values, nil
+}
+
+// fieldChanges are the custom field values a card write stores.
+type fieldChanges struct {
+	fields   userFields
+	existing map[uint]string
+	values   map[uint]string
+}
+
+// plan normalizes the inputs for a card whose current values are existing
+// (nil for a new card), fills empty fields with their default value and
+// rejects the write when a required field is left empty. An existing card is
+// only rejected for required fields the inputs clear, so that a field made
+// required later does not block unrelated edits of older cards.
+func (f userFields) plan(inputs []FieldInput, existing map[uint]string) (*fieldChanges, error) {
+	values := map[uint]string{}
+	for _, in := range inputs {
+		def, ok := f.find(in.FieldID)
+		if !ok {
+			return nil, fmt.Errorf("field %d not found", in.FieldID)
+		}
+		value, err := fieldtype.Normalize(def, in.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", def.Name, err)
+		}
+		values[def.ID] = value
+	}
+
+	var missing []string
+	for _, def := range f {
+		value, ok := values[def.ID]
+		if !ok {
+			value = existing[def.ID]
+		}
+		if value == "" && def.DefaultValue != "" {
+			value = def.DefaultValue
+			values[def.ID] = value
+		}
+		if value == "" && def.Required && (existing == nil || ok) {
+			missing = append(missing, def.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required fields: %s", strings.Join(missing, ", "))
+	}
+
+	for id, value := range values {
+		if existing[id] == value {
+			delete(values, id)
+		}
+	}
+	return &fieldChanges{fields: f, existing: existing, values: values}, nil
+}
+
+// write stores the values on the card and records a CUSTOM_FIELD history
+// entry for each.
+func (c *fieldChanges) write(tx *gorm.DB, cardID, actorID uint) error {
+	var changes []models.CardHistory
+	for _, def := range c.fields {
+		value, ok := c.values[def.ID]
+		if !ok {
+			continue
+		}
+		var fieldVal models.FieldValue
+		if err := tx.Where("field_id = ? AND card_id = ?", def.ID, cardID).
+			Assign(models.FieldValue{CardID: cardID, FieldID: def.ID, Value: value}).
+			FirstOrCreate(&fieldVal).Error; err != nil {
+			return err
+		}
+		if err := fieldtype.RelinkValues(tx, def, fieldVal.ID); err != nil {
+			return err
+		}
+		change := history.Change(cardID, actorID, models.CardHistoryCustomField, def.Name, c.existing[def.ID], value)
+		change.FieldID = def.ID
+		changes = append(changes, change)
+	}
+	return history.Record(tx, changes...)
+}
+
+// events returns a FIELD_CHANGED event per value written.
+func (c *fieldChanges) events(cardID, userID uint) []automation.Event {
+	var events []automation.Event
+	for _, def := range c.fields {
+		if _, ok := c.values[def.ID]; ok {
+			events = append(events, automation.Event{Trigger: models.TriggerFieldChanged, CardID: cardID, UserID: userID, FieldID: def.ID})
+		}
+	}
+	return events
+}
@@ -0,0 +1,375 @@
+package card
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Cognize-AI/client-cognize/models"
+)
+
+const (
+	VCardVersion3 = "3.0"
+	VCardVersion4 = "4.0"
+
+	// vcardFieldProp carries custom field values. The field name and type are
+	// stored in the X-NAME and X-TYPE parameters.
+	vcardFieldProp = "X-COGNIZE-FIELD"
+
+	vcardLineLimit = 75
+)
+
+// vcardField is a custom field value attached to a vCard.
+type vcardField struct {
+	Name  string
+	Type  string
+	Value string
+}
+
+type vcardProperty struct {
+	Name   string
+	Params map[string][]string
+	Value  string
+}
+
+func (p vcardProperty) hasType(t string) bool {
+	for _, v := range p.Params["TYPE"] {
+		if strings.EqualFold(v, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p vcardProperty) param(name string) string {
+	if v := p.Params[name]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+var vcardEscaper = strings.NewReplacer(`\`, `\\`, "\r\n", `\n`, "\n", `\n`, ",", `\,`, ";", `\;`)
+
+func escapeVCard(s string) string {
+	return vcardEscaper.Replace(s)
+}
+
+func unescapeVCard(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// splitVCard splits on sep, ignoring escaped separators.
+func splitVCard(s string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if s[i] == sep {
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func quoteParam(v string) string {
+	v = strings.ReplaceAll(v, `"`, "'")
+	if strings.ContainsAny(v, ";:,") {
+		return `"` + v + `"`
+	}
+	return v
+}
+
+// writeVCardLine writes a content line folded at 75 octets as RFC 6350
+// requires, without splitting UTF-8 sequences.
+func writeVCardLine(w io.Writer, line string) error {
+	for len(line) > vcardLineLimit {
+		cut := vcardLineLimit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		if _, err := io.WriteString(w, line[:cut]+"\r\n"); err != nil {
+			return err
+		}
+		line = " " + line[cut:]
+	}
+	_, err := io.WriteString(w, line+"\r\n")
+	return err
+}
+
+func splitName(name string) (family, given string) {
+	parts := strings.Fields(name)
+	switch len(parts) {
+	case 0:
+		return "", ""
+	case 1:
+		return "", parts[0]
+	default:
+		return parts[len(parts)-1], strings.Join(parts[:len(parts)-1], " ")
+	}
+}
+
+func writeVCard(w io.Writer, c models.Card, fields []vcardField, version string) error {
+	v3 := version == VCardVersion3
+	var lines []string
+	add := func(prop, value string) {
+		if value != "" {
+			lines = append(lines, prop+":"+value)
+		}
+	}
+	typed := func(prop, v3Type, v4Type, value string) {
+		if v3 {
+			add(prop+";TYPE="+v3Type, value)
+		} else {
+			add(prop+";TYPE="+v4Type, value)
+		}
+	}
+
+	lines = append(lines, "BEGIN:VCARD", "VERSION:"+version)
+	family, given := splitName(c.Name)
+	lines = append(lines, "FN:"+escapeVCard(c.Name))
+	lines = append(lines, fmt.Sprintf("N:%s;%s;;;", escapeVCard(family), escapeVCard(given)))
+	typed("EMAIL", "INTERNET,PREF", "home", escapeVCard(c.Email))
+	typed("EMAIL", "INTERNET,WORK", "work", escapeVCard(c.CompanyEmail))
+	typed("TEL", "CELL,VOICE", "cell", escapeVCard(c.Phone))
+	typed("TEL", "WORK,VOICE", "work", escapeVCard(c.CompanyPhone))
+	add("TITLE", escapeVCard(c.Designation))
+	add("ROLE", escapeVCard(c.CompanyRole))
+	add("ORG", escapeVCard(c.CompanyName))
+	if c.Location != "" {
+		typed("ADR", "HOME", "home", ";;"+escapeVCard(c.Location)+";;;;")
+	}
+	if c.CompanyLocation != "" {
+		typed("ADR", "WORK", "work", ";;"+escapeVCard(c.CompanyLocation)+";;;;")
+	}
+	add("URL", escapeVCard(c.ProfileUrl))
+	if v3 {
+		add("PHOTO;VALUE=URL", c.ImageURL)
+	} else {
+		add("PHOTO", c.ImageURL)
+	}
+	add("NOTE", escapeVCard(c.AISummary))
+	for _, f := range fields {
+		add(fmt.Sprintf("%s;X-NAME=%s;X-TYPE=%s", vcardFieldProp, quoteParam(f.Name), quoteParam(f.Type)), escapeVCard(f.Value))
+	}
+	lines = append(lines, "END:VCARD")
+
+	for _, line := range lines {
+		if err := writeVCardLine(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unfoldVCard joins continuation lines (starting with a space or tab).
+func unfoldVCard(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parseVCardLine parses "[group.]NAME;PARAM=a,b;PARAM2=c:value". vCard 2.1
+// style bare parameters ("TEL;WORK;VOICE") are treated as TYPE values.
+func parseVCardLine(line string) (vcardProperty, bool) {
+	inQuotes := false
+	colon := -1
+	for i := 0; i < len(line); i++ {
+		if line[i] == '"' {
+			inQuotes = !inQuotes
+		} else if line[i] == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return vcardProperty{}, false
+	}
+
+	var segments []string
+	start := 0
+	inQuotes = false
+	head := line[:colon]
+	for i := 0; i < len(head); i++ {
+		if head[i] == '"' {
+			inQuotes = !inQuotes
+		} else if head[i] == ';' && !inQuotes {
+			segments = append(segments, head[start:i])
+			start = i + 1
+		}
+	}
+	segments = append(segments, head[start:])
+
+	name := strings.ToUpper(segments[0])
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		name = name[dot+1:]
+	}
+	prop := vcardProperty{Name: name, Params: map[string][]string{}, Value: line[colon+1:]}
+	for _, seg := range segments[1:] {
+		key, value, ok := strings.Cut(seg, "=")
+		if !ok {
+			key, value = "TYPE", seg
+		}
+		key = strings.ToUpper(strings.TrimSpace(key))
+		for _, v := range strings.Split(value, ",") {
+			prop.Params[key] = append(prop.Params[key], strings.Trim(v, `"`))
+		}
+	}
+	return prop, true
+}
+
+// parseVCards returns the properties of every vCard in a .vcf file.
+func parseVCards(r io.Reader) ([][]vcardProperty, error) {
+	lines, err := unfoldVCard(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var cards [][]vcardProperty
+	var current []vcardProperty
+	inCard := false
+	for _, line := range lines {
+		prop, ok := parseVCardLine(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case prop.Name == "BEGIN" && strings.EqualFold(prop.Value, "VCARD"):
+			inCard = true
+			current = nil
+		case prop.Name == "END" && strings.EqualFold(prop.Value, "VCARD"):
+			if inCard {
+				cards = append(cards, current)
+			}
+			inCard = false
+		case inCard:
+			current = append(current, prop)
+		}
+	}
+	if inCard {
+		return nil, errors.New("vcard is missing END:VCARD")
+	}
+	return cards, nil
+}
+
+func adrValue(value string) string {
+	var parts []string
+	for _, p := range splitVCard(value, ';') {
+		if p = strings.TrimSpace(unescapeVCard(p)); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// cardFromVCard maps vCard properties onto a card. Work emails, phones and
+// addresses go to the company fields, others to the contact's own.
+func cardFromVCard(props []vcardProperty) (models.Card, []vcardField) {
+	var c models.Card
+	var fields []vcardField
+	var structuredName string
+
+	for _, p := range props {
+		value := unescapeVCard(p.Value)
+		switch p.Name {
+		case "FN":
+			c.Name = strings.TrimSpace(value)
+		case "N":
+			parts := splitVCard(p.Value, ';')
+			var names []string
+			for _, i := range []int{3, 1, 2, 0, 4} {
+				if i < len(parts) {
+					if n := strings.TrimSpace(unescapeVCard(parts[i])); n != "" {
+						names = append(names, n)
+					}
+				}
+			}
+			structuredName = strings.Join(names, " ")
+		case "EMAIL":
+			if p.hasType("WORK") && !p.hasType("PREF") {
+				if c.CompanyEmail == "" {
+					c.CompanyEmail = value
+				}
+			} else if c.Email == "" {
+				c.Email = value
+			}
+		case "TEL":
+			value = strings.TrimPrefix(value, "tel:")
+			if p.hasType("WORK") {
+				if c.CompanyPhone == "" {
+					c.CompanyPhone = value
+				}
+			} else if c.Phone == "" {
+				c.Phone = value
+			}
+		case "TITLE":
+			c.Designation = value
+		case "ROLE":
+			c.CompanyRole = value
+		case "ORG":
+			c.CompanyName = strings.TrimSpace(unescapeVCard(splitVCard(p.Value, ';')[0]))
+		case "ADR":
+			if p.hasType("WORK") {
+				c.CompanyLocation = adrValue(p.Value)
+			} else if c.Location == "" {
+				c.Location = adrValue(p.Value)
+			}
+		case "URL":
+			if c.ProfileUrl == "" {
+				c.ProfileUrl = value
+			}
+		case "PHOTO":
+			if strings.HasPrefix(p.Value, "http://") || strings.HasPrefix(p.Value, "https://") {
+				c.ImageURL = p.Value
+			}
+		case "NOTE":
+			c.AISummary = value
+		case vcardFieldProp:
+			if name := p.param("X-NAME"); name != "" {
+				fields = append(fields, vcardField{Name: name, Type: strings.ToUpper(p.param("X-TYPE")), Value: value})
+			}
+		}
+	}
+
+	if c.Name == "" {
+		c.Name = structuredName
+	}
+	if c.Email == "" && c.CompanyEmail != "" {
+		c.Email, c.CompanyEmail = c.CompanyEmail, ""
+	}
+	if c.Phone == "" && c.CompanyPhone != "" {
+		c.Phone, c.CompanyPhone = c.CompanyPhone, ""
+	}
+	return c, fields
+}
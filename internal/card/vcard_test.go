@@ -0,0 +1,171 @@
+package card
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/Cognize-AI/client-cognize/models"
+)
+
+func TestWriteVCardLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"short", "FN:Jane Doe"},
+		{"exactly the limit", "NOTE:" + strings.Repeat("a", vcardLineLimit-5)},
+		{"long ascii", "NOTE:" + strings.Repeat("abcdefghij", 20)},
+		{"long utf-8", "NOTE:" + strings.Repeat("héllo wörld ", 20)},
+		{"multibyte at the cut", "NOTE:" + strings.Repeat("a", vcardLineLimit-6) + "日本語"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := writeVCardLine(&buf, tt.line); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		out := buf.String()
+		if !strings.HasSuffix(out, "\r\n") {
+			t.Errorf("%s: output %q does not end with CRLF", tt.name, out)
+		}
+		physical := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+		for i, l := range physical {
+			if len(l) > vcardLineLimit {
+				t.Errorf("%s: line %d is %d octets, limit %d", tt.name, i, len(l), vcardLineLimit)
+			}
+			if !utf8.ValidString(l) {
+				t.Errorf("%s: line %d splits a UTF-8 sequence: %q", tt.name, i, l)
+			}
+			if i > 0 && !strings.HasPrefix(l, " ") {
+				t.Errorf("%s: continuation line %d does not start with a space", tt.name, i)
+			}
+		}
+		if len(tt.line) <= vcardLineLimit && len(physical) != 1 {
+			t.Errorf("%s: folded a line within the limit", tt.name)
+		}
+		unfolded, err := unfoldVCard(strings.NewReader(out))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(unfolded) != 1 || unfolded[0] != tt.line {
+			t.Errorf("%s: unfolded to %q, want %q", tt.name, unfolded, tt.line)
+		}
+	}
+}
+
+func TestEscapeVCard(t *testing.T) {
+	tests := []struct {
+		in      string
+		escaped string
+	}{
+		{"plain", "plain"},
+		{"Smith, John; Jr.", `Smith\, John\; Jr.`},
+		{"line one\nline two", `line one\nline two`},
+		{`back\slash`, `back\\slash`},
+	}
+	for _, tt := range tests {
+		if got := escapeVCard(tt.in); got != tt.escaped {
+			t.Errorf("escapeVCard(%q) = %q, want %q", tt.in, got, tt.escaped)
+		}
+		if got := unescapeVCard(tt.escaped); got != tt.in {
+			t.Errorf("unescapeVCard(%q) = %q, want %q", tt.escaped, got, tt.in)
+		}
+	}
+}
+
+func TestParseVCards(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    [][]vcardProperty
+		wantErr bool
+	}{
+		{
+			name:  "vcard 3.0 with type params",
+			input: "BEGIN:VCARD\r\nVERSION:3.0\r\nEMAIL;TYPE=INTERNET,WORK:jane@acme.com\r\nEND:VCARD\r\n",
+			want: [][]vcardProperty{{
+				{Name: "VERSION", Params: map[string][]string{}, Value: "3.0"},
+				{Name: "EMAIL", Params: map[string][]string{"TYPE": {"INTERNET", "WORK"}}, Value: "jane@acme.com"},
+			}},
+		},
+		{
+			name:  "vcard 2.1 bare params and groups",
+			input: "BEGIN:VCARD\nitem1.TEL;WORK;VOICE:+1 555 0100\nEND:VCARD\n",
+			want: [][]vcardProperty{{
+				{Name: "TEL", Params: map[string][]string{"TYPE": {"WORK", "VOICE"}}, Value: "+1 555 0100"},
+			}},
+		},
+		{
+			name:  "folded line and quoted param with a colon",
+			input: "BEGIN:VCARD\r\nX-COGNIZE-FIELD;X-NAME=\"Deal: size\";X-TYPE=COMPANY:10\r\n 00\r\nEND:VCARD\r\n",
+			want: [][]vcardProperty{{
+				{Name: "X-COGNIZE-FIELD", Params: map[string][]string{"X-NAME": {"Deal: size"}, "X-TYPE": {"COMPANY"}}, Value: "1000"},
+			}},
+		},
+		{
+			name:  "several cards and stray lines",
+			input: "junk\nBEGIN:VCARD\nFN:A\nEND:VCARD\nBEGIN:VCARD\nFN:B\nEND:VCARD\n",
+			want: [][]vcardProperty{
+				{{Name: "FN", Params: map[string][]string{}, Value: "A"}},
+				{{Name: "FN", Params: map[string][]string{}, Value: "B"}},
+			},
+		},
+		{
+			name:    "missing end",
+			input:   "BEGIN:VCARD\nFN:A\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		got, err := parseVCards(strings.NewReader(tt.input))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestVCardRoundTrip(t *testing.T) {
+	card := models.Card{
+		Name:            "Jane Q. Doe",
+		Designation:     "Head of Sales, EMEA",
+		Email:           "jane@example.com",
+		Phone:           "+1 415 555 0100",
+		Location:        "Berlin",
+		CompanyName:     "Acme; Inc",
+		CompanyRole:     "Buyer",
+		CompanyLocation: "Munich",
+		CompanyPhone:    "+49 89 1234",
+		CompanyEmail:    "jane@acme.com",
+		ProfileUrl:      "https://linkedin.com/in/jane",
+		ImageURL:        "https://example.com/jane.png",
+		AISummary:       strings.Repeat("Met at the conference.\n", 5),
+	}
+	fields := []vcardField{{Name: "Deal size", Type: "COMPANY", Value: "10,000"}}
+
+	for _, version := range []string{VCardVersion3, VCardVersion4} {
+		var buf bytes.Buffer
+		if err := writeVCard(&buf, card, fields, version); err != nil {
+			t.Fatal(err)
+		}
+		cards, err := parseVCards(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cards) != 1 {
+			t.Fatalf("version %s: parsed %d cards, want 1", version, len(cards))
+		}
+		got, gotFields := cardFromVCard(cards[0])
+		if !reflect.DeepEqual(got, card) {
+			t.Errorf("version %s: card = %+v, want %+v", version, got, card)
+		}
+		if !reflect.DeepEqual(gotFields, fields) {
+			t.Errorf("version %s: fields = %+v, want %+v", version, gotFields, fields)
+		}
+	}
+}
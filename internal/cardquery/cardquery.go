@@ -0,0 +1,290 @@
+package cardquery
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Cognize-AI/client-cognize/models"
+	"gorm.io/gorm"
+)
+
+// maxDepth limits how deeply filter groups may nest.
+const maxDepth = 3
+
+// numericPattern matches values that can be cast to numeric. It avoids "?"
+// so that it can be written into SQL next to placeholders.
+const numericPattern = `^-{0,1}[0-9]+([.][0-9]+){0,1}$`
+
+var numericOperators = map[string]string{
+	"gt":  ">",
+	"gte": ">=",
+	"lt":  "<",
+	"lte": "<=",
+}
+
+// sortColumns are the card columns besides models.CardFields a query can be
+// sorted by.
+var sortColumns = map[string]string{
+	"created_at": "cards.created_at",
+	"updated_at": "cards.updated_at",
+	"card_order": "cards.card_order",
+	"list":       "lists.list_order",
+}
+
+// fieldValue is the SQL value of a custom field on the current card, empty
+// when the card has none.
+func fieldValue(fieldID uint) string {
+	return fmt.Sprintf("COALESCE((SELECT field_values.value FROM field_values WHERE field_values.card_id = cards.id AND field_values.field_id = %d AND field_values.deleted_at IS NULL ORDER BY field_values.id DESC LIMIT 1), '')", fieldID)
+}
+
+func escapeLike(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(v)
+}
+
+func textCondition(expr string, cond models.CardCondition) (string, []interface{}, error) {
+	value := strings.TrimSpace(cond.Value)
+	switch cond.Operator {
+	case "equals":
+		return fmt.Sprintf("LOWER(TRIM(%s)) = LOWER(?)", expr), []interface{}{value}, nil
+	case "not_equals":
+		return fmt.Sprintf("LOWER(TRIM(%s)) <> LOWER(?)", expr), []interface{}{value}, nil
+	case "contains":
+		return expr + " ILIKE ?", []interface{}{"%" + escapeLike(value) + "%"}, nil
+	case "not_contains":
+		return expr + " NOT ILIKE ?", []interface{}{"%" + escapeLike(value) + "%"}, nil
+	case "starts_with":
+		return fmt.Sprintf("TRIM(%s) ILIKE ?", expr), []interface{}{escapeLike(value) + "%"}, nil
+	case "empty":
+		return fmt.Sprintf("TRIM(%s) = ''", expr), nil, nil
+	case "not_empty":
+		return fmt.Sprintf("TRIM(%s) <> ''", expr), nil, nil
+	}
+
+	op, ok := numericOperators[cond.Operator]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown operator %q", cond.Operator)
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return "", nil, fmt.Errorf("operator %s needs a number, got %q", cond.Operator, cond.Value)
+	}
+	return fmt.Sprintf("(CASE WHEN TRIM(%s) ~ '%s' THEN TRIM(%s)::numeric END) %s ?", expr, numericPattern, expr, op),
+		[]interface{}{number}, nil
+}
+
+func condition(cond models.CardCondition, fields Fields) (string, []interface{}, error) {
+	set := 0
+	for _, ok := range []bool{cond.Field != "", cond.FieldID != 0, len(cond.TagIDs) > 0, len(cond.ListIDs) > 0} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return "", nil, errors.New("a condition needs exactly one of field, field_id, tag_ids or list_ids")
+	}
+
+	switch {
+	case len(cond.TagIDs) > 0:
+		switch cond.Operator {
+		case "has_any":
+			return "cards.id IN (SELECT card_id FROM card_tags WHERE tag_id IN ?)", []interface{}{cond.TagIDs}, nil
+		case "has_all":
+			unique := map[uint]bool{}
+			for _, id := range cond.TagIDs {
+				unique[id] = true
+			}
+			return "(SELECT COUNT(DISTINCT tag_id) FROM card_tags WHERE card_id = cards.id AND tag_id IN ?) = ?", []interface{}{cond.TagIDs, len(unique)}, nil
+		case "has_none":
+			return "cards.id NOT IN (SELECT card_id FROM card_tags WHERE tag_id IN ?)", []interface{}{cond.TagIDs}, nil
+		}
+		return "", nil, fmt.Errorf("tag conditions use has_any, has_all or has_none, got %q", cond.Operator)
+	case len(cond.ListIDs) > 0:
+		switch cond.Operator {
+		case "in":
+			return "cards.list_id IN ?", []interface{}{cond.ListIDs}, nil
+		case "not_in":
+			return "cards.list_id NOT IN ?", []interface{}{cond.ListIDs}, nil
+		}
+		return "", nil, fmt.Errorf("list conditions use in or not_in, got %q", cond.Operator)
+	case cond.FieldID != 0:
+		return fieldCondition(cond, fields)
+	}
+
+	if _, ok := models.CardFields[cond.Field]; !ok {
+		return "", nil, fmt.Errorf("unknown field %q", cond.Field)
+	}
+	return textCondition(fmt.Sprintf("COALESCE(cards.%s, '')", cond.Field), cond)
+}
+
+func compile(filter models.CardFilter, fields Fields, depth int) (string, []interface{}, error) {
+	if depth > maxDepth {
+		return "", nil, fmt.Errorf("filter groups can nest at most %d levels", maxDepth)
+	}
+
+	joiner := " AND "
+	switch filter.Match {
+	case "", "all":
+	case "any":
+		joiner = " OR "
+	default:
+		return "", nil, fmt.Errorf("match must be all or any, got %q", filter.Match)
+	}
+
+	var parts []string
+	var args []interface{}
+	for _, cond := range filter.Conditions {
+		sql, condArgs, err := condition(cond, fields)
+		if err != nil {
+			return "", nil, err
+		}
+		parts = append(parts, sql)
+		args = append(args, condArgs...)
+	}
+	for _, group := range filter.Groups {
+		sql, groupArgs, err := compile(group, fields, depth+1)
+		if err != nil {
+			return "", nil, err
+		}
+		if sql != "" {
+			parts = append(parts, sql)
+			args = append(args, groupArgs...)
+		}
+	}
+
+	if len(parts) == 0 {
+		return "", nil, nil
+	}
+	return "(" + strings.Join(parts, joiner) + ")", args, nil
+}
+
+// owned reports whether every id is a row of model belonging to the user.
+func owned(db *gorm.DB, model interface{}, ids map[uint]bool, userID uint) (bool, error) {
+	if len(ids) == 0 {
+		return true, nil
+	}
+	list := make([]uint, 0, len(ids))
+	for id := range ids {
+		list = append(list, id)
+	}
+
+	var count int64
+	if err := db.Model(model).Where("id IN ? AND user_id = ?", list, userID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count == int64(len(ids)), nil
+}
+
+func collectIDs(filter models.CardFilter, tags, lists, fields map[uint]bool) {
+	for _, cond := range filter.Conditions {
+		for _, id := range cond.TagIDs {
+			tags[id] = true
+		}
+		for _, id := range cond.ListIDs {
+			lists[id] = true
+		}
+		if cond.FieldID != 0 {
+			fields[cond.FieldID] = true
+		}
+	}
+	for _, group := range filter.Groups {
+		collectIDs(group, tags, lists, fields)
+	}
+}
+
+// Validate checks the filter and sort, and that every tag, list and field
+// they reference belongs to the user.
+func Validate(db *gorm.DB, filter models.CardFilter, sort models.CardSort, userID uint) error {
+	fields, err := LoadFields(db, filter, sort, userID)
+	if err != nil {
+		return err
+	}
+	if _, _, err := compile(filter, fields, 0); err != nil {
+		return err
+	}
+	if _, err := orderBy(sort, fields); err != nil {
+		return err
+	}
+
+	tags, lists := map[uint]bool{}, map[uint]bool{}
+	collectIDs(filter, tags, lists, map[uint]bool{})
+
+	checks := []struct {
+		model interface{}
+		ids   map[uint]bool
+		name  string
+	}{
+		{&models.Tag{}, tags, "tag"},
+		{&models.List{}, lists, "list"},
+	}
+	for _, check := range checks {
+		ok, err := owned(db, check.model, check.ids, userID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("%s not found", check.name)
+		}
+	}
+	return nil
+}
+
+// Clause compiles filter to a condition on the cards table. It is empty when
+// the filter has no conditions. fields holds the custom fields the filter
+// refers to, see LoadFields.
+func Clause(filter models.CardFilter, fields Fields) (string, []interface{}, error) {
+	return compile(filter, fields, 0)
+}
+
+// Where narrows query, which selects from cards, to the cards matching
+// filter.
+func Where(query *gorm.DB, filter models.CardFilter, fields Fields) (*gorm.DB, error) {
+	sql, args, err := Clause(filter, fields)
+	if err != nil {
+		return nil, err
+	}
+	if sql == "" {
+		return query, nil
+	}
+	return query.Where(sql, args...), nil
+}
+
+func orderBy(sort models.CardSort, fields Fields) (string, error) {
+	direction := "ASC"
+	switch strings.ToLower(sort.Direction) {
+	case "", "asc":
+	case "desc":
+		direction = "DESC"
+	default:
+		return "", fmt.Errorf("direction must be asc or desc, got %q", sort.Direction)
+	}
+	if sort.Field != "" && sort.FieldID != 0 {
+		return "", errors.New("sort by either field or field_id")
+	}
+
+	switch {
+	case sort.FieldID != 0:
+		return fieldOrder(sort.FieldID, fields, direction)
+	case sort.Field == "":
+		return "lists.list_order ASC, cards.card_order ASC, cards.id ASC", nil
+	}
+
+	if column, ok := sortColumns[sort.Field]; ok {
+		return fmt.Sprintf("%s %s, cards.id %s", column, direction, direction), nil
+	}
+	if _, ok := models.CardFields[sort.Field]; ok {
+		return fmt.Sprintf("LOWER(COALESCE(cards.%s, '')) %s, cards.id %s", sort.Field, direction, direction), nil
+	}
+	return "", fmt.Errorf("cannot sort by %q", sort.Field)
+}
+
+// Order sorts query by sort, breaking ties by card id. Without a field the
+// cards keep their board order. The query must join lists.
+func Order(query *gorm.DB, sort models.CardSort, fields Fields) (*gorm.DB, error) {
+	order, err := orderBy(sort, fields)
+	if err != nil {
+		return nil, err
+	}
+	return query.Order(order), nil
+}
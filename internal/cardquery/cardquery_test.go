@@ -0,0 +1,237 @@
+package cardquery
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Cognize-AI/client-cognize/models"
+)
+
+func testFields() Fields {
+	def := func(id uint, name string, dataType models.FieldDataType) models.FieldDefinition {
+		d := models.FieldDefinition{Name: name, DataType: string(dataType)}
+		d.ID = id
+		return d
+	}
+	option := func(id uint, name string, position int) models.FieldOption {
+		o := models.FieldOption{Name: name, Position: position}
+		o.ID = id
+		return o
+	}
+	stage := def(5, "Stage", models.DataTypeSelect)
+	stage.Options = []models.FieldOption{option(50, "Open", 0), option(51, "Won", 1)}
+	industries := def(6, "Industries", models.DataTypeMultiSelect)
+	industries.Options = []models.FieldOption{option(60, "SaaS", 0), option(61, "Retail", 1)}
+	return Fields{
+		1: def(1, "Notes", models.DataTypeString),
+		2: def(2, "Employees", models.DataTypeNumber),
+		3: def(3, "Budget", models.DataTypeCurrency),
+		4: def(4, "Signed", models.DataTypeDate),
+		5: stage,
+		6: industries,
+	}
+}
+
+func TestClause(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter models.CardFilter
+		sql    string // exact SQL, or a fragment when contains is set
+		args   []interface{}
+		// contains checks sql as a fragment of the compiled clause.
+		contains bool
+	}{
+		{
+			name:   "no conditions",
+			filter: models.CardFilter{},
+			sql:    "",
+		},
+		{
+			name:   "built-in field equals",
+			filter: models.CardFilter{Conditions: []models.CardCondition{{Field: "email", Operator: "equals", Value: " Jane@Acme.com "}}},
+			sql:    "(LOWER(TRIM(COALESCE(cards.email, ''))) = LOWER(?))",
+			args:   []interface{}{"Jane@Acme.com"},
+		},
+		{
+			name:   "contains escapes like wildcards",
+			filter: models.CardFilter{Conditions: []models.CardCondition{{Field: "name", Operator: "contains", Value: "50%_off"}}},
+			sql:    "(COALESCE(cards.name, '') ILIKE ?)",
+			args:   []interface{}{`%50\%\_off%`},
+		},
+		{
+			name: "any of tags and lists",
+			filter: models.CardFilter{Match: "any", Conditions: []models.CardCondition{
+				{TagIDs: []uint{1, 2, 2}, Operator: "has_all"},
+				{ListIDs: []uint{3}, Operator: "not_in"},
+			}},
+			sql:  "((SELECT COUNT(DISTINCT tag_id) FROM card_tags WHERE card_id = cards.id AND tag_id IN ?) = ? OR cards.list_id NOT IN ?)",
+			args: []interface{}{[]uint{1, 2, 2}, 2, []uint{3}},
+		},
+		{
+			name: "nested group",
+			filter: models.CardFilter{
+				Conditions: []models.CardCondition{{Field: "phone", Operator: "not_empty"}},
+				Groups: []models.CardFilter{{Match: "any", Conditions: []models.CardCondition{
+					{Field: "name", Operator: "starts_with", Value: "A"},
+					{Field: "name", Operator: "starts_with", Value: "B"},
+				}}},
+			},
+			sql:  "(TRIM(COALESCE(cards.phone, '')) <> '' AND (TRIM(COALESCE(cards.name, '')) ILIKE ? OR TRIM(COALESCE(cards.name, '')) ILIKE ?))",
+			args: []interface{}{"A%", "B%"},
+		},
+		{
+			name:   "empty group is dropped",
+			filter: models.CardFilter{Groups: []models.CardFilter{{}}},
+			sql:    "",
+		},
+		{
+			name:     "built-in numeric comparison",
+			filter:   models.CardFilter{Conditions: []models.CardCondition{{Field: "name", Operator: "gt", Value: "10"}}},
+			sql:      "::numeric END) > ?",
+			args:     []interface{}{float64(10)},
+			contains: true,
+		},
+		{
+			name:     "string field not_equals matches cards without a value",
+			filter:   models.CardFilter{Conditions: []models.CardCondition{{FieldID: 1, Operator: "not_equals", Value: "x"}}},
+			sql:      "(NOT EXISTS (SELECT 1 FROM field_values WHERE field_values.field_id = 1 AND",
+			args:     []interface{}{"x"},
+			contains: true,
+		},
+		{
+			name:     "number between normalizes operands",
+			filter:   models.CardFilter{Conditions: []models.CardCondition{{FieldID: 2, Operator: "between", Values: []string{"1,000", "5e3"}}}},
+			sql:      "::numeric END) BETWEEN ? AND ?",
+			args:     []interface{}{float64(1000), float64(5000)},
+			contains: true,
+		},
+		{
+			name:     "currency symbol",
+			filter:   models.CardFilter{Conditions: []models.CardCondition{{FieldID: 3, Operator: "gte", Value: "$5,000"}}},
+			sql:      "::numeric END) >= ?",
+			args:     []interface{}{float64(5000)},
+			contains: true,
+		},
+		{
+			name:     "date compares the stored form",
+			filter:   models.CardFilter{Conditions: []models.CardCondition{{FieldID: 4, Operator: "lt", Value: "Jan 15, 2024"}}},
+			sql:      "field_values.value < ?",
+			args:     []interface{}{"2024-01-15"},
+			contains: true,
+		},
+		{
+			name:     "select in picks option ids",
+			filter:   models.CardFilter{Conditions: []models.CardCondition{{FieldID: 5, Operator: "not_in", Values: []string{"won", "Open"}}}},
+			sql:      "(NOT EXISTS (SELECT 1 FROM field_value_options",
+			args:     []interface{}{[]uint{51, 50}},
+			contains: true,
+		},
+		{
+			name:     "multi select has_all",
+			filter:   models.CardFilter{Conditions: []models.CardCondition{{FieldID: 6, Operator: "has_all", Values: []string{"SaaS", "Retail", "saas"}}}},
+			sql:      "field_value_options.field_id = 6 AND field_value_options.option_id IN ? AND field_values.card_id = cards.id) = ?)",
+			args:     []interface{}{[]uint{60, 61, 60}, 2},
+			contains: true,
+		},
+		{
+			name:     "field empty",
+			filter:   models.CardFilter{Conditions: []models.CardCondition{{FieldID: 2, Operator: "empty"}}},
+			sql:      "(NOT EXISTS (SELECT 1 FROM field_values WHERE field_values.field_id = 2",
+			contains: true,
+		},
+	}
+	for _, tt := range tests {
+		sql, args, err := Clause(tt.filter, testFields())
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if tt.contains && !strings.Contains(sql, tt.sql) {
+			t.Errorf("%s: sql %q does not contain %q", tt.name, sql, tt.sql)
+		}
+		if !tt.contains && sql != tt.sql {
+			t.Errorf("%s: sql = %q, want %q", tt.name, sql, tt.sql)
+		}
+		if !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("%s: args = %#v, want %#v", tt.name, args, tt.args)
+		}
+		if n := strings.Count(sql, "?"); n != len(args) {
+			t.Errorf("%s: %d placeholders for %d args in %q", tt.name, n, len(args), sql)
+		}
+	}
+}
+
+func TestClauseErrors(t *testing.T) {
+	deep := models.CardFilter{Conditions: []models.CardCondition{{Field: "name", Operator: "empty"}}}
+	for i := 0; i <= maxDepth; i++ {
+		deep = models.CardFilter{Groups: []models.CardFilter{deep}}
+	}
+
+	tests := []struct {
+		name string
+		cond models.CardCondition
+		// filter is used instead of cond when set.
+		filter *models.CardFilter
+		err    string
+	}{
+		{name: "nothing to match", cond: models.CardCondition{Operator: "equals"}, err: "exactly one of"},
+		{name: "two targets", cond: models.CardCondition{Field: "name", TagIDs: []uint{1}, Operator: "has_any"}, err: "exactly one of"},
+		{name: "unknown field", cond: models.CardCondition{Field: "password", Operator: "equals"}, err: `unknown field "password"`},
+		{name: "unknown operator", cond: models.CardCondition{Field: "name", Operator: "like"}, err: `unknown operator "like"`},
+		{name: "numeric operator needs number", cond: models.CardCondition{Field: "name", Operator: "lt", Value: "ten"}, err: "needs a number"},
+		{name: "bad tag operator", cond: models.CardCondition{TagIDs: []uint{1}, Operator: "in"}, err: "has_any, has_all or has_none"},
+		{name: "bad list operator", cond: models.CardCondition{ListIDs: []uint{1}, Operator: "has_any"}, err: "in or not_in"},
+		{name: "field not loaded", cond: models.CardCondition{FieldID: 99, Operator: "equals", Value: "x"}, err: "field 99 not found"},
+		{name: "operator not allowed for type", cond: models.CardCondition{FieldID: 5, Operator: "gt", Value: "Open"}, err: "select fields use"},
+		{name: "unknown option", cond: models.CardCondition{FieldID: 5, Operator: "in", Values: []string{"Lost"}}, err: `"Lost" is not an option`},
+		{name: "no options", cond: models.CardCondition{FieldID: 6, Operator: "has_any"}, err: "pick at least one option"},
+		{name: "between needs two", cond: models.CardCondition{FieldID: 2, Operator: "between", Value: "5"}, err: "needs 2 value(s)"},
+		{name: "invalid operand", cond: models.CardCondition{FieldID: 4, Operator: "gt", Value: "someday"}, err: "is not a date"},
+		{name: "bad match", filter: &models.CardFilter{Match: "some"}, err: "match must be all or any"},
+		{name: "too deep", filter: &deep, err: "nest at most"},
+	}
+	for _, tt := range tests {
+		filter := models.CardFilter{Conditions: []models.CardCondition{tt.cond}}
+		if tt.filter != nil {
+			filter = *tt.filter
+		}
+		_, _, err := Clause(filter, testFields())
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: err = %v, want it to mention %q", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestOrderBy(t *testing.T) {
+	tests := []struct {
+		name string
+		sort models.CardSort
+		want string
+		err  bool
+	}{
+		{name: "board order", sort: models.CardSort{}, want: "lists.list_order ASC, cards.card_order ASC, cards.id ASC"},
+		{name: "column", sort: models.CardSort{Field: "created_at", Direction: "DESC"}, want: "cards.created_at DESC, cards.id DESC"},
+		{name: "card field", sort: models.CardSort{Field: "name"}, want: "LOWER(COALESCE(cards.name, '')) ASC, cards.id ASC"},
+		{name: "number field", sort: models.CardSort{FieldID: 2, Direction: "desc"}, want: "::numeric END) DESC NULLS LAST, cards.id DESC"},
+		{name: "select field by option order", sort: models.CardSort{FieldID: 5}, want: "MIN(field_options.position)"},
+		{name: "date field as text", sort: models.CardSort{FieldID: 4}, want: "NULLIF(LOWER(TRIM(COALESCE("},
+		{name: "bad direction", sort: models.CardSort{Direction: "up"}, err: true},
+		{name: "field and field_id", sort: models.CardSort{Field: "name", FieldID: 2}, err: true},
+		{name: "unknown column", sort: models.CardSort{Field: "password"}, err: true},
+		{name: "field not loaded", sort: models.CardSort{FieldID: 99}, err: true},
+	}
+	for _, tt := range tests {
+		got, err := orderBy(tt.sort, testFields())
+		if (err != nil) != tt.err {
+			t.Errorf("%s: err = %v, want error %v", tt.name, err, tt.err)
+			continue
+		}
+		if !tt.err && !strings.Contains(got, tt.want) {
+			t.Errorf("%s: order = %q, want it to contain %q", tt.name, got, tt.want)
+		}
+		if strings.Contains(got, "?") {
+			t.Errorf("%s: order %q contains a placeholder", tt.name, got)
+		}
+	}
+}
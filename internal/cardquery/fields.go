@@ -0,0 +1,249 @@
+package cardquery
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Cognize-AI/client-cognize/internal/fieldtype"
+	"github.com/Cognize-AI/client-cognize/models"
+	"gorm.io/gorm"
+)
+
+// Fields are the custom fields a filter or sort refers to, by id, with their
+// options. Conditions and sorts on a field follow its data type.
+type Fields map[uint]models.FieldDefinition
+
+// typedOperators lists the operators each data type accepts. Types missing
+// from it are compared as text.
+var typedOperators = map[models.FieldDataType][]string{
+	models.DataTypeNumber:      {"equals", "not_equals", "gt", "gte", "lt", "lte", "between", "empty", "not_empty"},
+	models.DataTypeCurrency:    {"equals", "not_equals", "gt", "gte", "lt", "lte", "between", "empty", "not_empty"},
+	models.DataTypeDate:        {"equals", "not_equals", "gt", "gte", "lt", "lte", "between", "empty", "not_empty"},
+	models.DataTypeDateTime:    {"equals", "not_equals", "gt", "gte", "lt", "lte", "between", "empty", "not_empty"},
+	models.DataTypeBoolean:     {"equals", "not_equals", "empty", "not_empty"},
+	models.DataTypeSelect:      {"equals", "not_equals", "in", "not_in", "empty", "not_empty"},
+	models.DataTypeMultiSelect: {"has_any", "has_all", "has_none", "empty", "not_empty"},
+}
+
+// LoadFields loads the user's custom fields that filter and sort refer to.
+func LoadFields(db *gorm.DB, filter models.CardFilter, sort models.CardSort, userID uint) (Fields, error) {
+	ids := map[uint]bool{}
+	collectIDs(filter, map[uint]bool{}, map[uint]bool{}, ids)
+	if sort.FieldID != 0 {
+		ids[sort.FieldID] = true
+	}
+	fields := Fields{}
+	if len(ids) == 0 {
+		return fields, nil
+	}
+
+	list := make([]uint, 0, len(ids))
+	for id := range ids {
+		list = append(list, id)
+	}
+	var defs []models.FieldDefinition
+	if err := fieldtype.WithOptions(db).Where("id IN ? AND user_id = ?", list, userID).Find(&defs).Error; err != nil {
+		return nil, err
+	}
+	if len(defs) != len(ids) {
+		return nil, errors.New("field not found")
+	}
+	for _, def := range defs {
+		fields[def.ID] = def
+	}
+	return fields, nil
+}
+
+// valueExists matches the cards that have a value of the field passing pred.
+// The lookup uses the (field_id, card_id) index of field_values.
+func valueExists(fieldID uint, pred string, negate bool) string {
+	sql := fmt.Sprintf("EXISTS (SELECT 1 FROM field_values WHERE field_values.field_id = %d AND field_values.card_id = cards.id AND field_values.deleted_at IS NULL AND %s)", fieldID, pred)
+	if negate {
+		return "NOT " + sql
+	}
+	return sql
+}
+
+// optionExists matches the cards whose value of the field picks one of the
+// options, through the option links of field_value_options.
+func optionExists(fieldID uint, negate bool) string {
+	sql := fmt.Sprintf("EXISTS (SELECT 1 FROM field_value_options JOIN field_values ON field_values.id = field_value_options.field_value_id AND field_values.deleted_at IS NULL WHERE field_value_options.field_id = %d AND field_value_options.option_id IN ? AND field_values.card_id = cards.id)", fieldID)
+	if negate {
+		return "NOT " + sql
+	}
+	return sql
+}
+
+func numeric(expr string) string {
+	return fmt.Sprintf("(CASE WHEN TRIM(%s) ~ '%s' THEN TRIM(%s)::numeric END)", expr, numericPattern, expr)
+}
+
+// operands returns Values, or Value alone when Values is empty.
+func operands(cond models.CardCondition) []string {
+	if len(cond.Values) > 0 {
+		return cond.Values
+	}
+	if strings.TrimSpace(cond.Value) == "" {
+		return nil
+	}
+	return []string{cond.Value}
+}
+
+func optionIDs(def models.FieldDefinition, names []string) ([]uint, error) {
+	if len(names) == 0 {
+		return nil, errors.New("pick at least one option in values")
+	}
+	ids := make([]uint, 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, opt := range def.Options {
+			if strings.EqualFold(opt.Name, strings.TrimSpace(name)) {
+				ids = append(ids, opt.ID)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%q is not an option of %s", name, def.Name)
+		}
+	}
+	return ids, nil
+}
+
+// fieldCondition compiles a condition on a custom field according to its
+// data type. Operands are normalized like stored values, so "$5,000" matches
+// a currency value of 5000.00 and "Jan 15, 2024" a date of 2024-01-15.
+func fieldCondition(cond models.CardCondition, fields Fields) (string, []interface{}, error) {
+	def, ok := fields[cond.FieldID]
+	if !ok {
+		return "", nil, fmt.Errorf("field %d not found", cond.FieldID)
+	}
+	dataType := fieldtype.DataType(def)
+
+	switch cond.Operator {
+	case "empty":
+		return valueExists(def.ID, "TRIM(field_values.value) <> ''", true), nil, nil
+	case "not_empty":
+		return valueExists(def.ID, "TRIM(field_values.value) <> ''", false), nil, nil
+	}
+
+	allowed, typed := typedOperators[dataType]
+	if !typed {
+		// A card without a value does not equal or contain anything, so
+		// the negated operators match it too.
+		text := models.CardCondition{Operator: cond.Operator, Value: cond.Value}
+		negate := cond.Operator == "not_equals" || cond.Operator == "not_contains"
+		if negate {
+			text.Operator = strings.TrimPrefix(cond.Operator, "not_")
+		}
+		if text.Operator == "equals" {
+			if value, err := fieldtype.Normalize(def, cond.Value); err == nil {
+				text.Value = value
+			}
+		}
+		sql, args, err := textCondition("field_values.value", text)
+		if err != nil {
+			return "", nil, err
+		}
+		return valueExists(def.ID, sql, negate), args, nil
+	}
+	valid := false
+	for _, op := range allowed {
+		valid = valid || op == cond.Operator
+	}
+	if !valid {
+		return "", nil, fmt.Errorf("%s fields use %s, got %q", dataType, strings.Join(allowed, ", "), cond.Operator)
+	}
+
+	if dataType.HasOptions() {
+		ids, err := optionIDs(def, operands(cond))
+		if err != nil {
+			return "", nil, err
+		}
+		switch cond.Operator {
+		case "equals", "in", "has_any":
+			return optionExists(def.ID, false), []interface{}{ids}, nil
+		case "not_equals", "not_in", "has_none":
+			return optionExists(def.ID, true), []interface{}{ids}, nil
+		}
+		// has_all
+		unique := map[uint]bool{}
+		for _, id := range ids {
+			unique[id] = true
+		}
+		return fmt.Sprintf("(SELECT COUNT(DISTINCT field_value_options.option_id) FROM field_value_options JOIN field_values ON field_values.id = field_value_options.field_value_id AND field_values.deleted_at IS NULL WHERE field_value_options.field_id = %d AND field_value_options.option_id IN ? AND field_values.card_id = cards.id) = ?", def.ID),
+			[]interface{}{ids, len(unique)}, nil
+	}
+
+	values := operands(cond)
+	want := 1
+	if cond.Operator == "between" {
+		want = 2
+	}
+	if len(values) != want {
+		return "", nil, fmt.Errorf("operator %s needs %d value(s)", cond.Operator, want)
+	}
+	args := make([]interface{}, 0, want)
+	for _, raw := range values {
+		value, err := fieldtype.Normalize(def, raw)
+		if err != nil {
+			return "", nil, err
+		}
+		if value == "" {
+			return "", nil, fmt.Errorf("operator %s needs a value", cond.Operator)
+		}
+		if dataType == models.DataTypeNumber || dataType == models.DataTypeCurrency {
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return "", nil, err
+			}
+			args = append(args, n)
+			continue
+		}
+		args = append(args, value)
+	}
+
+	// Dates and datetimes are stored in a fixed format, so they compare as
+	// text.
+	expr := "field_values.value"
+	if dataType == models.DataTypeNumber || dataType == models.DataTypeCurrency {
+		expr = numeric(expr)
+	}
+	switch cond.Operator {
+	case "equals":
+		return valueExists(def.ID, expr+" = ?", false), args, nil
+	case "not_equals":
+		return valueExists(def.ID, expr+" = ?", true), args, nil
+	case "between":
+		return valueExists(def.ID, expr+" BETWEEN ? AND ?", false), args, nil
+	}
+	return valueExists(def.ID, fmt.Sprintf("%s %s ?", expr, numericOperators[cond.Operator]), false), args, nil
+}
+
+// fieldOrder sorts by a custom field: numbers and amounts numerically, select
+// fields by option order and other types as text. Cards without a value come
+// last.
+func fieldOrder(fieldID uint, fields Fields, direction string) (string, error) {
+	def, ok := fields[fieldID]
+	if !ok {
+		return "", fmt.Errorf("field %d not found", fieldID)
+	}
+
+	value := fieldValue(fieldID)
+	var expr string
+	switch dataType := fieldtype.DataType(def); {
+	case dataType == models.DataTypeNumber || dataType == models.DataTypeCurrency:
+		expr = numeric(value)
+	case dataType.HasOptions():
+		expr = fmt.Sprintf("(SELECT MIN(field_options.position) FROM field_value_options JOIN field_values ON field_values.id = field_value_options.field_value_id AND field_values.deleted_at IS NULL JOIN field_options ON field_options.id = field_value_options.option_id WHERE field_value_options.field_id = %d AND field_values.card_id = cards.id)", fieldID)
+	case dataType == models.DataTypeString:
+		// Numbers sort numerically, everything else as text after them.
+		return fmt.Sprintf("%s %s NULLS LAST, LOWER(%s) %s, cards.id %s",
+			numeric(value), direction, value, direction, direction), nil
+	default:
+		expr = fmt.Sprintf("NULLIF(LOWER(TRIM(%s)), '')", value)
+	}
+	return fmt.Sprintf("%s %s NULLS LAST, cards.id %s", expr, direction, direction), nil
+}
@@ -0,0 +1,84 @@
+package csvimport
+
+import (
+	"context"
+
+	"github.com/Cognize-AI/client-cognize/models"
+)
+
+type MappingTarget string
+
+const (
+	// TargetIgnore drops the column.
+	TargetIgnore MappingTarget = "ignore"
+	// TargetCard writes to a built-in card column named by Field.
+	TargetCard MappingTarget = "card"
+	// TargetField writes to the existing field definition FieldID.
+	TargetField MappingTarget = "field"
+	// TargetNewField creates a field definition named FieldName of FieldType
+	// and DataType, unless the user already has one.
+	TargetNewField MappingTarget = "new_field"
+	// TargetTags splits the cell on the tag separator and tags the card.
+	TargetTags MappingTarget = "tags"
+)
+
+type ColumnMapping struct {
+	Column    string        `json:"column"`
+	Target    MappingTarget `json:"target"`
+	Field     string        `json:"field"`
+	FieldID   uint          `json:"field_id"`
+	FieldName string        `json:"field_name"`
+	FieldType string        `json:"field_type"`
+	DataType  string        `json:"data_type"`
+	Currency  string        `json:"currency"`
+}
+
+type UploadCSVRes struct {
+	ID         uint       `json:"id"`
+	FileName   string     `json:"file_name"`
+	Headers    []string   `json:"headers"`
+	SampleRows [][]string `json:"sample_rows"`
+	TotalRows  int        `json:"total_rows"`
+}
+
+type RunImportReq struct {
+	ID             uint            `uri:"id" binding:"required"`
+	ListID         uint            `json:"list_id"`
+	DryRun         bool            `json:"dry_run"`
+	SkipDuplicates bool            `json:"skip_duplicates"`
+	TagSeparator   string          `json:"tag_separator"`
+	Mappings       []ColumnMapping `json:"mappings"`
+}
+
+// RowError reports why a row cannot be imported. Row is the 1-based data row,
+// not counting the header.
+type RowError struct {
+	Row     int      `json:"row"`
+	Reasons []string `json:"reasons"`
+}
+
+// RowDuplicate points at an existing card or an earlier row of the same file
+// that the row most likely duplicates.
+type RowDuplicate struct {
+	Row            int    `json:"row"`
+	CardID         uint   `json:"card_id,omitempty"`
+	DuplicateOfRow int    `json:"duplicate_of_row,omitempty"`
+	Reason         string `json:"reason"`
+}
+
+type RunImportRes struct {
+	DryRun        bool           `json:"dry_run"`
+	TotalRows     int            `json:"total_rows"`
+	Created       int            `json:"created"`
+	Skipped       int            `json:"skipped"`
+	Failed        int            `json:"failed"`
+	Errors        []RowError     `json:"errors"`
+	Duplicates    []RowDuplicate `json:"duplicates"`
+	NewFields     []string       `json:"new_fields"`
+	CreatedFields []uint         `json:"created_fields"`
+}
+
+type Service interface {
+	UploadCSV(ctx context.Context, fileName string, content []byte, user models.User) (*UploadCSVRes, error)
+	RunImport(ctx context.Context, req RunImportReq, user models.User) (*RunImportRes, error)
+}
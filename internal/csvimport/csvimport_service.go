@@ -0,0 +1,510 @@
+package csvimport
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Cognize-AI/client-cognize/config"
+	"github.com/Cognize-AI/client-cognize/internal/analytics"
+	"github.com/Cognize-AI/client-cognize/internal/automation"
+	"github.com/Cognize-AI/client-cognize/internal/fieldtype"
+	"github.com/Cognize-AI/client-cognize/internal/stagerules"
+	"github.com/Cognize-AI/client-cognize/logger"
+	"github.com/Cognize-AI/client-cognize/models"
+	"github.com/Cognize-AI/client-cognize/util"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	sampleRowCount      = 5
+	defaultTagSeparator = ","
+	defaultTagColor     = "#BBDEFB"
+)
+
+type service struct {
+	timeout time.Duration
+	DB      *gorm.DB
+}
+
+func NewService() Service {
+	return &service{
+		time.Duration(20) * time.Second,
+		config.DB,
+	}
+}
+
+func parseCSV(content []byte) ([]string, [][]string, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	r.TrimLeadingSpace = true
+
+	headers, err := r.Read()
+	if err == io.EOF {
+		return nil, nil, errors.New("csv file is empty")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid csv: %w", err)
+	}
+	for i := range headers {
+		headers[i] = strings.TrimSpace(headers[i])
+	}
+
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid csv: %w", err)
+	}
+	return headers, rows, nil
+}
+
+func (s *service) UploadCSV(ctx context.Context, fileName string, content []byte, user models.User) (*UploadCSVRes, error) {
+	headers, rows, err := parseCSV(content)
+	if err != nil {
+		return nil, err
+	}
+
+	imp := models.Import{
+		FileName: fileName,
+		Content:  string(content),
+		Status:   string(models.ImportStatusUploaded),
+		UserID:   user.ID,
+	}
+	if err := s.DB.Create(&imp).Error; err != nil {
+		logger.Logger.Error("failed to save csv import", zap.Error(err))
+		return nil, err
+	}
+
+	sample := rows
+	if len(sample) > sampleRowCount {
+		sample = sample[:sampleRowCount]
+	}
+
+	return &UploadCSVRes{
+		ID:         imp.ID,
+		FileName:   imp.FileName,
+		Headers:    headers,
+		SampleRows: sample,
+		TotalRows:  len(rows),
+	}, nil
+}
+
+// resolvedMapping is a ColumnMapping bound to a column index and, for custom
+// fields, to a field definition.
+type resolvedMapping struct {
+	ColumnMapping
+	index    int
+	fieldDef *models.FieldDefinition
+	// pending is the definition a new_field column creates.
+	pending *models.FieldDefinition
+}
+
+func columnIndex(headers []string, column string) int {
+	for i, h := range headers {
+		if h == column {
+			return i
+		}
+	}
+	for i, h := range headers {
+		if strings.EqualFold(h, column) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *service) resolveMappings(headers []string, mappings []ColumnMapping, user models.User) ([]resolvedMapping, error) {
+	var resolved []resolvedMapping
+	for _, m := range mappings {
+		if m.Target == TargetIgnore {
+			continue
+		}
+		idx := columnIndex(headers, m.Column)
+		if idx < 0 {
+			return nil, fmt.Errorf("column %q not found in csv", m.Column)
+		}
+		rm := resolvedMapping{ColumnMapping: m, index: idx}
+
+		switch m.Target {
+		case TargetCard:
+			if _, ok := models.CardFields[m.Field]; !ok {
+				return nil, fmt.Errorf("unknown card field %q for column %q", m.Field, m.Column)
+			}
+		case TargetField:
+			var fieldDef models.FieldDefinition
+			fieldtype.WithOptions(s.DB).Where("id = ? AND user_id = ?", m.FieldID, user.ID).First(&fieldDef)
+			if fieldDef.ID == 0 {
+				return nil, fmt.Errorf("field definition %d not found for column %q", m.FieldID, m.Column)
+			}
+			if fieldDef.Archived {
+				return nil, fmt.Errorf("field %q is archived, column %q cannot be imported into it", fieldDef.Name, m.Column)
+			}
+			rm.fieldDef = &fieldDef
+		case TargetNewField:
+			if strings.TrimSpace(m.FieldName) == "" {
+				return nil, fmt.Errorf("field_name is required for column %q", m.Column)
+			}
+			if !models.FieldDefinitionType(m.FieldType).IsFieldTypeValid() {
+				return nil, fmt.Errorf("field_type must be CONTACT or COMPANY for column %q", m.Column)
+			}
+			var fieldDef models.FieldDefinition
+			fieldtype.WithOptions(s.DB).Where("name = ? AND user_id = ? AND type = ?", m.FieldName, user.ID, m.FieldType).First(&fieldDef)
+			if fieldDef.ID != 0 {
+				if fieldDef.Archived {
+					return nil, fmt.Errorf("field %q is archived, column %q cannot be imported into it", fieldDef.Name, m.Column)
+				}
+				rm.fieldDef = &fieldDef
+				break
+			}
+			if models.FieldDataType(m.DataType).HasOptions() {
+				return nil, fmt.Errorf("create %s field %q before importing column %q", m.DataType, m.FieldName, m.Column)
+			}
+			rm.pending = &models.FieldDefinition{
+				Name:     m.FieldName,
+				UserID:   user.ID,
+				Type:     m.FieldType,
+				DataType: m.DataType,
+				Currency: m.Currency,
+			}
+			if err := fieldtype.Prepare(rm.pending); err != nil {
+				return nil, fmt.Errorf("column %q: %w", m.Column, err)
+			}
+		case TargetTags:
+		default:
+			return nil, fmt.Errorf("unknown target %q for column %q", m.Target, m.Column)
+		}
+		resolved = append(resolved, rm)
+	}
+	return resolved, nil
+}
+
+// importRow is a parsed CSV row ready to be written.
+type importRow struct {
+	number int
+	card   models.Card
+	values []fieldCell
+	tags   []string
+}
+
+type fieldCell struct {
+	mapping *resolvedMapping
+	value   string
+}
+
+// entryValues returns the row's values of existing fields, by field id, with
+// the defaults the card is created with. A list can only require fields that
+// exist already.
+func (row importRow) entryValues(db *gorm.DB, userID uint) (map[uint]string, error) {
+	values := map[uint]string{}
+	for _, v := range row.values {
+		if v.mapping.fieldDef != nil {
+			values[v.mapping.fieldDef.ID] = v.value
+		}
+	}
+	return fieldtype.WithDefaults(db, userID, values)
+}
+
+func buildRow(number int, record []string, mappings []resolvedMapping, tagSeparator string) (importRow, []string) {
+	row := importRow{number: number}
+	var reasons []string
+	for i := range mappings {
+		m := &mappings[i]
+		var cell string
+		if m.index < len(record) {
+			cell = strings.TrimSpace(record[m.index])
+		}
+		if cell == "" {
+			continue
+		}
+		switch m.Target {
+		case TargetCard:
+			*models.CardFields[m.Field](&row.card) = cell
+		case TargetField, TargetNewField:
+			def := m.fieldDef
+			if def == nil {
+				def = m.pending
+			}
+			value, err := fieldtype.Normalize(*def, cell)
+			if err != nil {
+				reasons = append(reasons, fmt.Sprintf("%s: %s", def.Name, err))
+				continue
+			}
+			if value != "" {
+				row.values = append(row.values, fieldCell{m, value})
+			}
+		case TargetTags:
+			for _, name := range strings.Split(cell, tagSeparator) {
+				if name = strings.TrimSpace(name); name != "" {
+					row.tags = append(row.tags, name)
+				}
+			}
+		}
+	}
+
+	c := row.card
+	if strings.TrimSpace(c.Name) == "" && strings.TrimSpace(c.Email) == "" && strings.TrimSpace(c.ProfileUrl) == "" {
+		reasons = append(reasons, "one of name, email or profile_url is required")
+	}
+	for _, email := range []string{c.Email, c.CompanyEmail} {
+		if email == "" {
+			continue
+		}
+		if _, err := mail.ParseAddress(email); err != nil {
+			reasons = append(reasons, "invalid email: "+email)
+		}
+	}
+	return row, reasons
+}
+
+func (s *service) RunImport(ctx context.Context, req RunImportReq, user models.User) (*RunImportRes, error) {
+	var imp models.Import
+	var list models.List
+
+	s.DB.Where("id = ? AND user_id = ?", req.ID, user.ID).First(&imp)
+	if imp.ID == 0 {
+		return nil, errors.New("import not found")
+	}
+	if !req.DryRun && imp.Status == string(models.ImportStatusCompleted) {
+		return nil, errors.New("import already completed")
+	}
+
+	s.DB.Where("id = ? AND user_id = ?", req.ListID, user.ID).First(&list)
+	if list.ID == 0 {
+		logger.Logger.Error("list not found", zap.String("list_id", strconv.Itoa(int(req.ListID))))
+		return nil, errors.New("list not found")
+	}
+
+	headers, records, err := parseCSV([]byte(imp.Content))
+	if err != nil {
+		return nil, err
+	}
+
+	mappings, err := s.resolveMappings(headers, req.Mappings, user)
+	if err != nil {
+		return nil, err
+	}
+
+	tagSeparator := req.TagSeparator
+	if tagSeparator == "" {
+		tagSeparator = defaultTagSeparator
+	}
+
+	var existing []models.Card
+	if err := s.DB.
+		Select("cards.id, cards.email, cards.profile_url").
+		Joins("JOIN lists ON lists.id = cards.list_id AND lists.deleted_at IS NULL").
+		Where("lists.user_id = ? AND (cards.email <> '' OR cards.profile_url <> '')", user.ID).
+		Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	cardByEmail := map[string]uint{}
+	cardByProfile := map[string]uint{}
+	for _, c := range existing {
+		if k := util.NormalizeEmail(c.Email); k != "" {
+			cardByEmail[k] = c.ID
+		}
+		if k := util.NormalizeProfileURL(c.ProfileUrl); k != "" {
+			cardByProfile[k] = c.ID
+		}
+	}
+
+	res := &RunImportRes{
+		DryRun:     req.DryRun,
+		TotalRows:  len(records),
+		Errors:     []RowError{},
+		Duplicates: []RowDuplicate{},
+	}
+	for _, m := range mappings {
+		if m.Target == TargetNewField && m.fieldDef == nil {
+			res.NewFields = append(res.NewFields, m.FieldName)
+		}
+	}
+
+	rowByEmail := map[string]int{}
+	rowByProfile := map[string]int{}
+	var rows []importRow
+
+	for i, record := range records {
+		row, reasons := buildRow(i+1, record, mappings, tagSeparator)
+		if len(reasons) > 0 {
+			res.Errors = append(res.Errors, RowError{Row: row.number, Reasons: reasons})
+			res.Failed++
+			continue
+		}
+
+		emailKey := util.NormalizeEmail(row.card.Email)
+		profileKey := util.NormalizeProfileURL(row.card.ProfileUrl)
+		var dup *RowDuplicate
+		switch {
+		case emailKey != "" && cardByEmail[emailKey] != 0:
+			dup = &RowDuplicate{Row: row.number, CardID: cardByEmail[emailKey], Reason: "email"}
+		case profileKey != "" && cardByProfile[profileKey] != 0:
+			dup = &RowDuplicate{Row: row.number, CardID: cardByProfile[profileKey], Reason: "profile_url"}
+		case emailKey != "" && rowByEmail[emailKey] != 0:
+			dup = &RowDuplicate{Row: row.number, DuplicateOfRow: rowByEmail[emailKey], Reason: "email"}
+		case profileKey != "" && rowByProfile[profileKey] != 0:
+			dup = &RowDuplicate{Row: row.number, DuplicateOfRow: rowByProfile[profileKey], Reason: "profile_url"}
+		}
+		if dup != nil {
+			res.Duplicates = append(res.Duplicates, *dup)
+			if req.SkipDuplicates {
+				res.Skipped++
+				continue
+			}
+		}
+
+		// Rows accepted so far count against the list's WIP limit.
+		values, err := row.entryValues(s.DB, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		err = stagerules.CheckBehind(s.DB, list, row.card, values, len(rows))
+		var ruleErr *stagerules.Error
+		if errors.As(err, &ruleErr) {
+			res.Errors = append(res.Errors, RowError{Row: row.number, Reasons: ruleErr.Reasons()})
+			res.Failed++
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if emailKey != "" && rowByEmail[emailKey] == 0 {
+			rowByEmail[emailKey] = row.number
+		}
+		if profileKey != "" && rowByProfile[profileKey] == 0 {
+			rowByProfile[profileKey] = row.number
+		}
+		rows = append(rows, row)
+	}
+
+	if req.DryRun {
+		res.Created = len(rows)
+		return res, nil
+	}
+
+	var events []automation.Event
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		newFields := map[string]*models.FieldDefinition{}
+		for i := range mappings {
+			m := &mappings[i]
+			if m.Target != TargetNewField || m.fieldDef != nil {
+				continue
+			}
+			if fieldDef, ok := newFields[m.FieldType+":"+m.FieldName]; ok {
+				m.fieldDef = fieldDef
+				continue
+			}
+			fieldDef := *m.pending
+			if err := tx.Create(&fieldDef).Error; err != nil {
+				return fmt.Errorf("failed to create field %q: %w", m.FieldName, err)
+			}
+			m.fieldDef = &fieldDef
+			newFields[m.FieldType+":"+m.FieldName] = &fieldDef
+			res.CreatedFields = append(res.CreatedFields, fieldDef.ID)
+		}
+
+		var userTags []models.Tag
+		if err := tx.Where("user_id = ?", user.ID).Find(&userTags).Error; err != nil {
+			return err
+		}
+		tagByName := map[string]*models.Tag{}
+		for i := range userTags {
+			tagByName[strings.ToLower(userTags[i].Name)] = &userTags[i]
+		}
+
+		if err := stagerules.Lock(tx, &list); err != nil {
+			return err
+		}
+
+		var maxOrder float64
+		tx.Model(&models.Card{}).Select("COALESCE(MAX(card_order), 0)").Scan(&maxOrder)
+
+		for _, row := range rows {
+			// The rows were checked above; checking again under the lock
+			// catches cards that entered the list in the meantime.
+			values, err := row.entryValues(tx, user.ID)
+			if err != nil {
+				return err
+			}
+			if err := stagerules.Check(tx, list, row.card, values); err != nil {
+				return fmt.Errorf("row %d: %w", row.number, err)
+			}
+
+			maxOrder++
+			c := row.card
+			c.ListID = list.ID
+			c.CardOrder = maxOrder
+			if err := tx.Create(&c).Error; err != nil {
+				return fmt.Errorf("row %d: %w", row.number, err)
+			}
+			if err := analytics.RecordTransitions(tx, analytics.Transition(c.ID, 0, list.ID, user.ID)); err != nil {
+				return fmt.Errorf("row %d: %w", row.number, err)
+			}
+			events = append(events, automation.Event{Trigger: models.TriggerCardCreated, CardID: c.ID, UserID: user.ID})
+
+			for _, v := range row.values {
+				fieldVal := models.FieldValue{
+					CardID:  c.ID,
+					FieldID: v.mapping.fieldDef.ID,
+					Value:   v.value,
+				}
+				if err := tx.Create(&fieldVal).Error; err != nil {
+					return fmt.Errorf("row %d: %w", row.number, err)
+				}
+				if err := fieldtype.RelinkValues(tx, *v.mapping.fieldDef, fieldVal.ID); err != nil {
+					return fmt.Errorf("row %d: %w", row.number, err)
+				}
+			}
+
+			var tags []models.Tag
+			added := map[uint]bool{}
+			for _, name := range row.tags {
+				t, ok := tagByName[strings.ToLower(name)]
+				if !ok {
+					t = &models.Tag{Name: name, Color: defaultTagColor, UserID: user.ID}
+					if err := tx.Create(t).Error; err != nil {
+						return fmt.Errorf("row %d: %w", row.number, err)
+					}
+					tagByName[strings.ToLower(name)] = t
+				}
+				if !added[t.ID] {
+					added[t.ID] = true
+					tags = append(tags, *t)
+				}
+			}
+			if len(tags) > 0 {
+				if err := tx.Model(&c).Association("Tags").Append(&tags); err != nil {
+					return fmt.Errorf("row %d: %w", row.number, err)
+				}
+			}
+			res.Created++
+		}
+
+		var cardIDs []uint
+		for _, event := range events {
+			cardIDs = append(cardIDs, event.CardID)
+		}
+		if err := fieldtype.FillDefaults(tx, cardIDs...); err != nil {
+			return err
+		}
+
+		imp.Status = string(models.ImportStatusCompleted)
+		return tx.Save(&imp).Error
+	})
+	if err != nil {
+		logger.Logger.Error("csv import failed", zap.Uint("import_id", imp.ID), zap.Error(err))
+		return nil, err
+	}
+	automation.Dispatch(events...)
+
+	return res, nil
+}
@@ -0,0 +1,55 @@
+package export
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/Cognize-AI/client-cognize/models"
+)
+
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+)
+
+type ExportCardsReq struct {
+	Format Format `form:"format"`
+	ListID uint   `form:"list_id"`
+	TagIDs []uint `form:"tag_id"`
+}
+
+// CardRow is one exported card. It is written as-is for NDJSON and flattened
+// into columns for CSV.
+type CardRow struct {
+	ID               uint              `json:"id"`
+	Name             string            `json:"name"`
+	Designation      string            `json:"designation"`
+	Email            string            `json:"email"`
+	Phone            string            `json:"phone"`
+	ImageURL         string            `json:"image_url"`
+	Location         string            `json:"location"`
+	CompanyName      string            `json:"company_name"`
+	CompanyRole      string            `json:"company_role"`
+	CompanyLocation  string            `json:"company_location"`
+	CompanyPhone     string            `json:"company_phone"`
+	CompanyEmail     string            `json:"company_email"`
+	ProfileURL       string            `json:"profile_url"`
+	AISummary        string            `json:"ai_summary"`
+	ListID           uint              `json:"list_id"`
+	List             string            `json:"list"`
+	Tags             []string          `json:"tags"`
+	CustomFields     map[string]string `json:"custom_fields"`
+	LatestActivity   string            `json:"latest_activity"`
+	LatestActivityAt *time.Time        `json:"latest_activity_at"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+}
+
+type Service interface {
+	// ExportCards validates the request before writing anything to w, so an
+	// error returned without output can still be reported to the client.
+	ExportCards(ctx context.Context, req ExportCardsReq, user models.User, w io.Writer) error
+}
@@ -0,0 +1,83 @@
+package export
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Cognize-AI/client-cognize/logger"
+	"github.com/Cognize-AI/client-cognize/util"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{s}
+}
+
+// streamWriter sends the download headers on the first write, so that the
+// handler can still answer with a JSON error if nothing was written yet.
+type streamWriter struct {
+	c           *gin.Context
+	contentType string
+	fileName    string
+	started     bool
+}
+
+func (w *streamWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.c.Header("Content-Type", w.contentType)
+		w.c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", w.fileName))
+		w.c.Status(http.StatusOK)
+	}
+	return w.c.Writer.Write(p)
+}
+
+func (w *streamWriter) Flush() {
+	if w.started {
+		w.c.Writer.Flush()
+	}
+}
+
+func (h *Handler) ExportCards(c *gin.Context) {
+	currentUser, valid := util.GetCurrentUser(c)
+	if !valid {
+		return
+	}
+
+	var req ExportCardsReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Format == "" {
+		req.Format = FormatCSV
+	}
+
+	w := &streamWriter{
+		c:           c,
+		contentType: "text/csv; charset=utf-8",
+		fileName:    fmt.Sprintf("cognize-export-%s.%s", time.Now().Format("20060102-150405"), req.Format),
+	}
+	if req.Format == FormatNDJSON {
+		w.contentType = "application/x-ndjson"
+	}
+
+	if err := h.Service.ExportCards(c, req, currentUser, w); err != nil {
+		logger.Logger.Error("Error exporting cards :", zap.Error(err))
+		if !w.started {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	if !w.started {
+		// No cards matched: still send the (empty) file.
+		_, _ = w.Write(nil)
+	}
+}
@@ -0,0 +1,300 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Cognize-AI/client-cognize/config"
+	"github.com/Cognize-AI/client-cognize/logger"
+	"github.com/Cognize-AI/client-cognize/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const batchSize = 500
+
+var builtinColumns = []string{
+	"id", "name", "designation", "email", "phone", "image_url", "location",
+	"company_name", "company_role", "company_location", "company_phone", "company_email",
+	"profile_url", "ai_summary", "list", "tags", "latest_activity", "latest_activity_at",
+	"created_at", "updated_at",
+}
+
+type service struct {
+	timeout time.Duration
+	DB      *gorm.DB
+}
+
+func NewService() Service {
+	return &service{
+		time.Duration(20) * time.Second,
+		config.DB,
+	}
+}
+
+// rowWriter serializes CardRows in one output format.
+type rowWriter interface {
+	WriteRow(row CardRow) error
+	Flush() error
+}
+
+type csvRowWriter struct {
+	w      *csv.Writer
+	fields []string
+}
+
+func newCSVRowWriter(w io.Writer, fields []string) (*csvRowWriter, error) {
+	cw := csv.NewWriter(w)
+	// Field names are user input, so they are escaped like values.
+	header := append(append([]string{}, builtinColumns...), escapeCells(append([]string{}, fields...))...)
+	if err := cw.Write(header); err != nil {
+		return nil, err
+	}
+	return &csvRowWriter{w: cw, fields: fields}, nil
+}
+
+func (c *csvRowWriter) WriteRow(row CardRow) error {
+	var latestAt string
+	if row.LatestActivityAt != nil {
+		latestAt = row.LatestActivityAt.Format(time.RFC3339)
+	}
+	record := []string{
+		strconv.Itoa(int(row.ID)), row.Name, row.Designation, row.Email, row.Phone, row.ImageURL, row.Location,
+		row.CompanyName, row.CompanyRole, row.CompanyLocation, row.CompanyPhone, row.CompanyEmail,
+		row.ProfileURL, row.AISummary, row.List, strings.Join(row.Tags, ", "), row.LatestActivity, latestAt,
+		row.CreatedAt.Format(time.RFC3339), row.UpdatedAt.Format(time.RFC3339),
+	}
+	for _, f := range c.fields {
+		record = append(record, row.CustomFields[f])
+	}
+	return c.w.Write(escapeCells(record))
+}
+
+// escapeCells prefixes cells that a spreadsheet would read as a formula with
+// a single quote, so exported values are shown as text and never evaluated.
+// Signed numbers and phone numbers such as "+1 (415) 555-0100" are kept as
+// they are.
+func escapeCells(record []string) []string {
+	for i, cell := range record {
+		if cell == "" || !strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+			continue
+		}
+		if (cell[0] == '+' || cell[0] == '-') && isSignedNumber(cell[1:]) {
+			continue
+		}
+		record[i] = "'" + cell
+	}
+	return record
+}
+
+// isSignedNumber reports whether s, following a sign, starts with a digit and
+// holds nothing but digits and the separators of numbers and phone numbers.
+func isSignedNumber(s string) bool {
+	if s == "" || s[0] < '0' || s[0] > '9' {
+		return false
+	}
+	for _, r := range s {
+		if (r < '0' || r > '9') && !strings.ContainsRune(" .,()-/", r) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *csvRowWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type ndjsonRowWriter struct {
+	enc *json.Encoder
+}
+
+func (n *ndjsonRowWriter) WriteRow(row CardRow) error {
+	return n.enc.Encode(row)
+}
+
+func (n *ndjsonRowWriter) Flush() error {
+	return nil
+}
+
+// fieldColumns names one column per field definition. A name used by both a
+// CONTACT and a COMPANY field is suffixed with the type to keep columns unique.
+func fieldColumns(defs []models.FieldDefinition) map[uint]string {
+	count := map[string]int{}
+	for _, d := range defs {
+		count[d.Name]++
+	}
+	columns := map[uint]string{}
+	for _, d := range defs {
+		name := d.Name
+		if count[d.Name] > 1 {
+			name = fmt.Sprintf("%s (%s)", d.Name, strings.ToLower(d.Type))
+		}
+		columns[d.ID] = name
+	}
+	return columns
+}
+
+type latestActivity struct {
+	CardID    uint
+	Content   string
+	CreatedAt time.Time
+}
+
+func (s *service) ExportCards(ctx context.Context, req ExportCardsReq, user models.User, w io.Writer) error {
+	if req.Format != FormatCSV && req.Format != FormatNDJSON {
+		return errors.New("format must be csv or ndjson")
+	}
+
+	query := s.DB.Model(&models.Card{}).
+		Joins("JOIN lists ON lists.id = cards.list_id AND lists.deleted_at IS NULL").
+		Where("lists.user_id = ?", user.ID)
+
+	if req.ListID != 0 {
+		var list models.List
+		s.DB.Where("id = ? AND user_id = ?", req.ListID, user.ID).First(&list)
+		if list.ID == 0 {
+			logger.Logger.Error("list not found", zap.String("list_id", strconv.Itoa(int(req.ListID))))
+			return errors.New("list not found")
+		}
+		query = query.Where("cards.list_id = ?", list.ID)
+	}
+	if len(req.TagIDs) > 0 {
+		query = query.Where("cards.id IN (SELECT card_id FROM card_tags WHERE tag_id IN ?)", req.TagIDs)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var defs []models.FieldDefinition
+	if err := s.DB.Where("user_id = ?", user.ID).Order("type ASC, field_order ASC, id ASC").Find(&defs).Error; err != nil {
+		return err
+	}
+	columns := fieldColumns(defs)
+	var fieldNames []string
+	for _, d := range defs {
+		fieldNames = append(fieldNames, columns[d.ID])
+	}
+
+	var out rowWriter
+	if req.Format == FormatCSV {
+		cw, err := newCSVRowWriter(w, fieldNames)
+		if err != nil {
+			return err
+		}
+		out = cw
+	} else {
+		out = &ndjsonRowWriter{enc: json.NewEncoder(w)}
+	}
+
+	var lastID uint
+	for {
+		var cards []models.Card
+		if err := query.
+			Preload("Tags").
+			Preload("List").
+			Where("cards.id > ?", lastID).
+			Order("cards.id ASC").
+			Limit(batchSize).
+			Find(&cards).Error; err != nil {
+			return err
+		}
+		if len(cards) == 0 {
+			break
+		}
+		lastID = cards[len(cards)-1].ID
+
+		cardIDs := make([]uint, len(cards))
+		for i, c := range cards {
+			cardIDs[i] = c.ID
+		}
+
+		var values []models.FieldValue
+		if err := s.DB.Where("card_id IN ?", cardIDs).Find(&values).Error; err != nil {
+			return err
+		}
+		valuesByCard := map[uint]map[string]string{}
+		for _, v := range values {
+			name, ok := columns[v.FieldID]
+			if !ok {
+				continue
+			}
+			if valuesByCard[v.CardID] == nil {
+				valuesByCard[v.CardID] = map[string]string{}
+			}
+			valuesByCard[v.CardID][name] = v.Value
+		}
+
+		var activities []latestActivity
+		if err := s.DB.Raw(`
+			SELECT DISTINCT ON (card_id) card_id, content, created_at
+			FROM activities
+			WHERE card_id IN ? AND deleted_at IS NULL
+			ORDER BY card_id, created_at DESC`, cardIDs).
+			Scan(&activities).Error; err != nil {
+			return err
+		}
+		activityByCard := map[uint]latestActivity{}
+		for _, a := range activities {
+			activityByCard[a.CardID] = a
+		}
+
+		for _, c := range cards {
+			row := CardRow{
+				ID:              c.ID,
+				Name:            c.Name,
+				Designation:     c.Designation,
+				Email:           c.Email,
+				Phone:           c.Phone,
+				ImageURL:        c.ImageURL,
+				Location:        c.Location,
+				CompanyName:     c.CompanyName,
+				CompanyRole:     c.CompanyRole,
+				CompanyLocation: c.CompanyLocation,
+				CompanyPhone:    c.CompanyPhone,
+				CompanyEmail:    c.CompanyEmail,
+				ProfileURL:      c.ProfileUrl,
+				AISummary:       c.AISummary,
+				ListID:          c.ListID,
+				List:            c.List.Name,
+				Tags:            []string{},
+				CustomFields:    valuesByCard[c.ID],
+				CreatedAt:       c.CreatedAt,
+				UpdatedAt:       c.UpdatedAt,
+			}
+			if row.CustomFields == nil {
+				row.CustomFields = map[string]string{}
+			}
+			for _, t := range c.Tags {
+				row.Tags = append(row.Tags, t.Name)
+			}
+			if a, ok := activityByCard[c.ID]; ok {
+				createdAt := a.CreatedAt
+				row.LatestActivity = a.Content
+				row.LatestActivityAt = &createdAt
+			}
+			if err := out.WriteRow(row); err != nil {
+				return err
+			}
+		}
+
+		if err := out.Flush(); err != nil {
+			return err
+		}
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+		if len(cards) < batchSize {
+			break
+		}
+	}
+
+	return out.Flush()
+}
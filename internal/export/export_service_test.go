@@ -0,0 +1,84 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+)
+
+func TestEscapeCells(t *testing.T) {
+	tests := []struct {
+		cell string
+		want string
+	}{
+		{"+1 (415) 555-0100", "+1 (415) 555-0100"},
+		{"+14155550100", "+14155550100"},
+		{"-5", "-5"},
+		{"-42.5", "-42.5"},
+		{"=1+1", "'=1+1"},
+		{"+A1", "'+A1"},
+		{"-2+3+cmd|' /C calc'!A0", "'-2+3+cmd|' /C calc'!A0"},
+		{"@SUM(A1:A2)", "'@SUM(A1:A2)"},
+		{"-", "'-"},
+		{"\tx", "'\tx"},
+		{"Acme", "Acme"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := escapeCells([]string{tt.cell})[0]; got != tt.want {
+			t.Errorf("escapeCells(%q) = %q, want %q", tt.cell, got, tt.want)
+		}
+	}
+}
+
+func TestIsSignedNumber(t *testing.T) {
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{"1 (415) 555-0100", true},
+		{"5", true},
+		{"1,000.50", true},
+		{"", false},
+		{"A1", false},
+		{"1+1", false},
+		{" 1", false},
+	}
+	for _, tt := range tests {
+		if got := isSignedNumber(tt.s); got != tt.want {
+			t.Errorf("isSignedNumber(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestCSVHeaderEscapesFieldNames(t *testing.T) {
+	var buf bytes.Buffer
+	fields := []string{"Budget", "=HYPERLINK(\"http://x\")"}
+	w, err := newCSVRowWriter(&buf, fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	header, err := csv.NewReader(&buf).Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(header) != len(builtinColumns)+2 {
+		t.Fatalf("header has %d columns, want %d", len(header), len(builtinColumns)+2)
+	}
+	if header[0] != "id" {
+		t.Errorf("header[0] = %q, want id", header[0])
+	}
+	if got := header[len(builtinColumns)]; got != "Budget" {
+		t.Errorf("field column = %q, want Budget", got)
+	}
+	if got := header[len(builtinColumns)+1]; got != "'=HYPERLINK(\"http://x\")" {
+		t.Errorf("formula field column = %q, want it escaped", got)
+	}
+	if fields[1] != "=HYPERLINK(\"http://x\")" {
+		t.Errorf("field names were modified: %q", fields[1])
+	}
+}
@@ -6,13 +6,28 @@ import (
 	"github.com/Cognize-AI/client-cognize/models"
 )
 
+type OptionReq struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
 type CreateFieldReq struct {
 	FieldName string `json:"field_name"`
 	Type      string `json:"type"`
+	// DataType is one of the models.FieldDataType values, string when empty.
+	DataType string      `json:"data_type"`
+	Currency string      `json:"currency"`
+	Options  []OptionReq `json:"options"`
+	Required bool        `json:"required"`
+	// DefaultValue fills the field on new cards and on existing cards that
+	// leave it empty.
+	DefaultValue string `json:"default_value"`
 }
 
 type CreateFieldRes struct {
 	ID uint `json:"id"`
+	// Backfilled counts the existing cards given the default value.
+	Backfilled int64 `json:"backfilled"`
 }
 
 type InsertFieldValReq struct {
@@ -23,27 +38,172 @@ type InsertFieldValReq struct {
 
 type InsertFieldValRes struct {
 	ID uint `json:"id"`
+	// Value is the value as stored, after normalization.
+	Value string `json:"value"`
+}
+
+type Option struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Color    string `json:"color"`
+	Position int    `json:"position"`
+}
+
+type FieldIDReq struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+type OptionUsage struct {
+	Option
+	// CardCount is the number of cards whose value picks the option.
+	CardCount int64 `json:"card_count"`
+}
+
+type GetOptionsRes struct {
+	FieldID uint          `json:"field_id"`
+	Options []OptionUsage `json:"options"`
+}
+
+type CreateOptionReq struct {
+	ID    uint   `uri:"id" binding:"required"`
+	Name  string `json:"name" binding:"required"`
+	Color string `json:"color"`
+}
+
+type UpdateOptionReq struct {
+	ID       uint `uri:"id" binding:"required"`
+	OptionID uint `uri:"option_id" binding:"required"`
+	// Name and Color are left unchanged when empty.
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type DeleteOptionReq struct {
+	ID       uint `uri:"id" binding:"required"`
+	OptionID uint `uri:"option_id" binding:"required"`
+	// ReplacementID is the option that takes the deleted one's place in the
+	// values that pick it. It is required when any value does.
+	ReplacementID uint `form:"replacement_id"`
+}
+
+type DeleteOptionRes struct {
+	ReplacedValues int `json:"replaced_values"`
+}
+
+type ReorderOptionsReq struct {
+	ID        uint   `uri:"id" binding:"required"`
+	OptionIDs []uint `json:"option_ids" binding:"required"`
+}
+
+type GetFieldsReq struct {
+	// IncludeArchived also lists archived fields.
+	IncludeArchived bool `form:"include_archived"`
 }
 
 type FieldWithSample struct {
-	ID          uint    `json:"id"`
-	Name        string  `json:"name"`
-	Type        string  `json:"type"`
-	SampleValue *string `json:"sample_value"`
+	ID           uint     `json:"id"`
+	Name         string   `json:"name"`
+	Type         string   `json:"type"`
+	DataType     string   `json:"data_type"`
+	Currency     string   `json:"currency,omitempty"`
+	Options      []Option `json:"options,omitempty" gorm:"-"`
+	FieldOrder   float64  `json:"field_order"`
+	Archived     bool     `json:"archived"`
+	Required     bool     `json:"required"`
+	DefaultValue string   `json:"default_value"`
+	SampleValue  *string  `json:"sample_value"`
 }
 
 type GetFieldsRes struct {
 	Fields []FieldWithSample `json:"fields"`
 }
 
+// UpdateFieldDef changes the fields that are set. A new default value is
+// backfilled to the cards that leave the field empty.
 type UpdateFieldDef struct {
-	ID   uint   `json:"id"`
-	Name string `json:"name"`
+	ID           uint    `json:"id"`
+	Name         string  `json:"name"`
+	Required     *bool   `json:"required"`
+	DefaultValue *string `json:"default_value"`
+}
+
+type DeleteFieldRes struct {
+	ID uint `json:"id"`
+	// DeletedValues counts the card values removed with the field.
+	DeletedValues int64 `json:"deleted_values"`
+	// DisabledRules counts the automation rules that used the field. They
+	// are disabled and lose the conditions and actions on it.
+	DisabledRules int `json:"disabled_rules"`
+	// UpdatedViews counts the saved views that lost a column, condition or
+	// sort on the field.
+	UpdatedViews int `json:"updated_views"`
+}
+
+type ArchiveFieldReq struct {
+	ID       uint `uri:"id" binding:"required"`
+	Archived bool `json:"archived"`
+}
+
+type MoveFieldReq struct {
+	PrevField uint `json:"prev_field"`
+	CurrField uint `json:"curr_field" binding:"required"`
+	NextField uint `json:"next_field"`
+}
+
+type ConvertFieldReq struct {
+	ID       uint        `uri:"id" binding:"required"`
+	DataType string      `json:"data_type" binding:"required"`
+	Currency string      `json:"currency"`
+	Options  []OptionReq `json:"options"`
+	// DryRun only reports what would happen.
+	DryRun bool `json:"dry_run"`
+	// ClearInvalid converts the field even when some values do not convert,
+	// clearing them. Without it such a conversion is not applied.
+	ClearInvalid bool `json:"clear_invalid"`
+}
+
+type ConversionFailure struct {
+	CardID   uint   `json:"card_id"`
+	CardName string `json:"card_name"`
+	Value    string `json:"value"`
+	Error    string `json:"error"`
+}
+
+// IncompatibleView is a saved view whose filter or sort on a field does not
+// work with the field's new data type.
+type IncompatibleView struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+// ConvertFieldRes is the migration report of a data type change.
+type ConvertFieldRes struct {
+	FieldID uint   `json:"field_id"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Total   int    `json:"total"`
+	// Converted values are valid for the new type, some after normalization.
+	Converted int                 `json:"converted"`
+	Changed   int                 `json:"changed"`
+	Failed    []ConversionFailure `json:"failed"`
+	// IncompatibleViews must be edited before the conversion is applied.
+	IncompatibleViews []IncompatibleView `json:"incompatible_views"`
+	Applied           bool               `json:"applied"`
 }
 
 type Service interface {
 	CreateField(c context.Context, req CreateFieldReq, user models.User) (*CreateFieldRes, error)
 	InsertFieldVal(c context.Context, req InsertFieldValReq, user models.User) (*InsertFieldValRes, error)
-	GetFields(c context.Context, user models.User) (*GetFieldsRes, error)
+	GetFields(c context.Context, req GetFieldsReq, user models.User) (*GetFieldsRes, error)
 	UpdateFieldDefinition(c context.Context, req UpdateFieldDef, user models.User) error
+	DeleteField(c context.Context, req FieldIDReq, user models.User) (*DeleteFieldRes, error)
+	ArchiveField(c context.Context, req ArchiveFieldReq, user models.User) error
+	MoveField(c context.Context, req MoveFieldReq, user models.User) error
+	ConvertField(c context.Context, req ConvertFieldReq, user models.User) (*ConvertFieldRes, error)
+	GetOptions(c context.Context, req FieldIDReq, user models.User) (*GetOptionsRes, error)
+	CreateOption(c context.Context, req CreateOptionReq, user models.User) (*Option, error)
+	UpdateOption(c context.Context, req UpdateOptionReq, user models.User) (*Option, error)
+	DeleteOption(c context.Context, req DeleteOptionReq, user models.User) (*DeleteOptionRes, error)
+	ReorderOptions(c context.Context, req ReorderOptionsReq, user models.User) (*GetOptionsRes, error)
 }
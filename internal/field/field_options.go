@@ -0,0 +1,579 @@
+package field
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Cognize-AI/client-cognize/internal/fieldtype"
+	"github.com/Cognize-AI/client-cognize/internal/history"
+	"github.com/Cognize-AI/client-cognize/logger"
+	"github.com/Cognize-AI/client-cognize/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// findOptionField loads one of the user's select or multi_select fields with
+// its options.
+func (s *service) findOptionField(id uint, user models.User) (*models.FieldDefinition, error) {
+	var fieldDef models.FieldDefinition
+	fieldtype.WithOptions(s.DB).Where("id = ? AND user_id = ?", id, user.ID).First(&fieldDef)
+	if fieldDef.ID == 0 {
+		logger.Logger.Error("field not found", zap.String("field_id", strconv.Itoa(int(id))))
+		return nil, errors.New("field definition does not exist")
+	}
+	if !fieldtype.DataType(fieldDef).HasOptions() {
+		return nil, errors.New("only select and multi_select fields have options")
+	}
+	return &fieldDef, nil
+}
+
+func findOption(fieldDef *models.FieldDefinition, optionID uint) (*models.FieldOption, error) {
+	for i := range fieldDef.Options {
+		if fieldDef.Options[i].ID == optionID {
+			return &fieldDef.Options[i], nil
+		}
+	}
+	logger.Logger.Error("option not found", zap.String("option_id", strconv.Itoa(int(optionID))))
+	return nil, errors.New("option not found")
+}
+
+func optionNameTaken(fieldDef *models.FieldDefinition, name string, exceptID uint) bool {
+	for _, opt := range fieldDef.Options {
+		if opt.ID != exceptID && strings.EqualFold(opt.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// valuesWithOption returns the values that pick the option.
+func valuesWithOption(tx *gorm.DB, optionID uint) ([]models.FieldValue, error) {
+	var values []models.FieldValue
+	err := tx.Joins("JOIN field_value_options ON field_value_options.field_value_id = field_values.id").
+		Where("field_value_options.option_id = ?", optionID).
+		Order("field_values.id ASC").
+		Find(&values).Error
+	return values, err
+}
+
+// optionInValue reports whether a stored value picks the option.
+func optionInValue(def models.FieldDefinition, value, name string) bool {
+	names, multi := fieldtype.Typed(def, value).([]string)
+	if !multi {
+		return value != "" && strings.EqualFold(value, name)
+	}
+	for _, picked := range names {
+		if strings.EqualFold(picked, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// remapDefault applies an option change to the field's default value, see
+// fieldtype.Remap.
+func remapDefault(tx *gorm.DB, fieldDef *models.FieldDefinition, from, to string) error {
+	if fieldDef.DefaultValue == "" {
+		return nil
+	}
+	value, err := fieldtype.Remap(*fieldDef, fieldDef.DefaultValue, from, to)
+	if err != nil {
+		return fmt.Errorf("default_value: %w", err)
+	}
+	if value == fieldDef.DefaultValue {
+		return nil
+	}
+	fieldDef.DefaultValue = value
+	return tx.Model(&models.FieldDefinition{}).Where("id = ?", fieldDef.ID).Update("default_value", value).Error
+}
+
+// conditionUsesOption reports whether an automation condition value names the
+// option. Conditions compare text, so the value is either the option name or,
+// on multi_select fields, a stored list.
+func conditionUsesOption(def models.FieldDefinition, value, name string) bool {
+	if strings.EqualFold(strings.TrimSpace(value), name) {
+		return true
+	}
+	return fieldtype.DataType(def) == models.DataTypeMultiSelect && optionInValue(def, value, name)
+}
+
+// ruleUsingOption returns the name of one of the user's automation rules whose
+// conditions or SET_FIELD actions on the field name the option, if any.
+func ruleUsingOption(db *gorm.DB, userID uint, def models.FieldDefinition, name string) (string, error) {
+	var rules []models.AutomationRule
+	if err := db.Where("user_id = ?", userID).Order("id ASC").Find(&rules).Error; err != nil {
+		return "", err
+	}
+	for _, rule := range rules {
+		for _, cond := range rule.Conditions {
+			if cond.FieldID == def.ID && conditionUsesOption(def, cond.Value, name) {
+				return rule.Name, nil
+			}
+		}
+		for _, action := range rule.Actions {
+			if models.AutomationActionType(action.Type) == models.ActionSetField && action.FieldID == def.ID &&
+				optionInValue(def, action.Value, name) {
+				return rule.Name, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// remapRules applies an option change to the condition values and SET_FIELD
+// actions on the field in the user's automation rules, like remapDefault does
+// for the default value.
+func remapRules(tx *gorm.DB, userID uint, def models.FieldDefinition, from, to string) error {
+	var rules []models.AutomationRule
+	if err := tx.Where("user_id = ?", userID).Find(&rules).Error; err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		changed := false
+		for i := range rule.Conditions {
+			cond := &rule.Conditions[i]
+			if cond.FieldID != def.ID || !conditionUsesOption(def, cond.Value, from) {
+				continue
+			}
+			value := to
+			if !strings.EqualFold(strings.TrimSpace(cond.Value), from) {
+				remapped, err := fieldtype.Remap(def, cond.Value, from, to)
+				if err != nil {
+					return fmt.Errorf("rule %q: %w", rule.Name, err)
+				}
+				value = remapped
+			}
+			cond.Value = value
+			changed = true
+		}
+		for i := range rule.Actions {
+			action := &rule.Actions[i]
+			if models.AutomationActionType(action.Type) != models.ActionSetField || action.FieldID != def.ID ||
+				!optionInValue(def, action.Value, from) {
+				continue
+			}
+			value, err := fieldtype.Remap(def, action.Value, from, to)
+			if err != nil {
+				return fmt.Errorf("rule %q: %w", rule.Name, err)
+			}
+			action.Value = value
+			changed = true
+		}
+		if !changed {
+			continue
+		}
+		if err := tx.Omit(clause.Associations).Save(&rule).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filterUsesOption reports whether a condition on the field in filter, or in
+// one of its groups, names the option.
+func filterUsesOption(filter models.CardFilter, fieldID uint, name string) bool {
+	for _, cond := range filter.Conditions {
+		if cond.FieldID != fieldID {
+			continue
+		}
+		for _, value := range append([]string{cond.Value}, cond.Values...) {
+			if strings.EqualFold(strings.TrimSpace(value), name) {
+				return true
+			}
+		}
+	}
+	for _, group := range filter.Groups {
+		if filterUsesOption(group, fieldID, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// remapFilter replaces option from with option to in the conditions on the
+// field. It reports whether filter changed.
+func remapFilter(filter *models.CardFilter, fieldID uint, from, to string) bool {
+	changed := false
+	for i := range filter.Conditions {
+		cond := &filter.Conditions[i]
+		if cond.FieldID != fieldID {
+			continue
+		}
+		if strings.EqualFold(strings.TrimSpace(cond.Value), from) {
+			cond.Value = to
+			changed = true
+		}
+		if len(cond.Values) == 0 {
+			continue
+		}
+		values := make([]string, 0, len(cond.Values))
+		seen := map[string]bool{}
+		for _, value := range cond.Values {
+			if strings.EqualFold(strings.TrimSpace(value), from) {
+				value = to
+				changed = true
+			}
+			if !seen[strings.ToLower(value)] {
+				seen[strings.ToLower(value)] = true
+				values = append(values, value)
+			}
+		}
+		cond.Values = values
+	}
+	for i := range filter.Groups {
+		if remapFilter(&filter.Groups[i], fieldID, from, to) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// viewUsingOption returns the name of one of the user's saved views whose
+// filter names the option, if any.
+func viewUsingOption(db *gorm.DB, userID, fieldID uint, name string) (string, error) {
+	var views []models.SavedView
+	if err := db.Where("user_id = ?", userID).Order("id ASC").Find(&views).Error; err != nil {
+		return "", err
+	}
+	for _, view := range views {
+		if filterUsesOption(view.Filter, fieldID, name) {
+			return view.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// remapViews applies an option change to the conditions on the field in the
+// user's saved views. Conditions name options, so without it a view would
+// stop compiling after a rename.
+func remapViews(tx *gorm.DB, userID, fieldID uint, from, to string) error {
+	var views []models.SavedView
+	if err := tx.Where("user_id = ?", userID).Find(&views).Error; err != nil {
+		return err
+	}
+	for _, view := range views {
+		if !remapFilter(&view.Filter, fieldID, from, to) {
+			continue
+		}
+		if err := tx.Omit(clause.Associations).Save(&view).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *service) GetOptions(c context.Context, req FieldIDReq, user models.User) (*GetOptionsRes, error) {
+	fieldDef, err := s.findOptionField(req.ID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	var counts []struct {
+		OptionID  uint
+		CardCount int64
+	}
+	if err := s.DB.Table("field_value_options").
+		Select("field_value_options.option_id, COUNT(DISTINCT field_values.card_id) AS card_count").
+		Joins("JOIN field_values ON field_values.id = field_value_options.field_value_id AND field_values.deleted_at IS NULL").
+		Joins("JOIN cards ON cards.id = field_values.card_id AND cards.deleted_at IS NULL").
+		Where("field_value_options.field_id = ?", fieldDef.ID).
+		Group("field_value_options.option_id").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	byOption := map[uint]int64{}
+	for _, count := range counts {
+		byOption[count.OptionID] = count.CardCount
+	}
+
+	res := &GetOptionsRes{FieldID: fieldDef.ID, Options: []OptionUsage{}}
+	for _, opt := range fieldDef.Options {
+		res.Options = append(res.Options, OptionUsage{Option: toOption(opt), CardCount: byOption[opt.ID]})
+	}
+	return res, nil
+}
+
+func (s *service) CreateOption(c context.Context, req CreateOptionReq, user models.User) (*Option, error) {
+	fieldDef, err := s.findOptionField(req.ID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	if optionNameTaken(fieldDef, name, 0) {
+		return nil, fmt.Errorf("option %q already exists", name)
+	}
+	if len(fieldDef.Options) >= fieldtype.MaxOptions {
+		return nil, fmt.Errorf("a field can have at most %d options", fieldtype.MaxOptions)
+	}
+
+	opt := models.FieldOption{FieldID: fieldDef.ID, Name: name, Color: req.Color}
+	if opt.Color == "" {
+		opt.Color = fieldtype.DefaultOptionColor
+	}
+	if n := len(fieldDef.Options); n > 0 {
+		opt.Position = fieldDef.Options[n-1].Position + 1
+	}
+	if err := s.DB.Create(&opt).Error; err != nil {
+		logger.Logger.Error("failed to create option", zap.Error(err))
+		return nil, err
+	}
+
+	res := toOption(opt)
+	return &res, nil
+}
+
+// UpdateOption renames or recolors an option. A rename rewrites every value
+// that picks the option.
+func (s *service) UpdateOption(c context.Context, req UpdateOptionReq, user models.User) (*Option, error) {
+	fieldDef, err := s.findOptionField(req.ID, user)
+	if err != nil {
+		return nil, err
+	}
+	opt, err := findOption(fieldDef, req.OptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	oldName := opt.Name
+	if name := strings.TrimSpace(req.Name); name != "" {
+		if optionNameTaken(fieldDef, name, opt.ID) {
+			return nil, fmt.Errorf("option %q already exists", name)
+		}
+		opt.Name = name
+	}
+	if req.Color != "" {
+		opt.Color = req.Color
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(opt).Updates(map[string]interface{}{"name": opt.Name, "color": opt.Color}).Error; err != nil {
+			return err
+		}
+		if opt.Name == oldName {
+			return nil
+		}
+		if err := remapDefault(tx, fieldDef, oldName, opt.Name); err != nil {
+			return err
+		}
+		if err := remapRules(tx, user.ID, *fieldDef, oldName, opt.Name); err != nil {
+			return err
+		}
+		if err := remapViews(tx, user.ID, fieldDef.ID, oldName, opt.Name); err != nil {
+			return err
+		}
+
+		values, err := valuesWithOption(tx, opt.ID)
+		if err != nil {
+			return err
+		}
+		var changes []models.CardHistory
+		for _, v := range values {
+			before := v.Value
+			value, err := fieldtype.Remap(*fieldDef, v.Value, oldName, opt.Name)
+			if err != nil {
+				return fmt.Errorf("card %d: %w", v.CardID, err)
+			}
+			if err := tx.Model(&v).Update("value", value).Error; err != nil {
+				return err
+			}
+			change := history.Change(v.CardID, user.ID, models.CardHistoryCustomField, fieldDef.Name, before, value)
+			change.FieldID = fieldDef.ID
+			changes = append(changes, change)
+		}
+		return history.Record(tx, changes...)
+	})
+	if err != nil {
+		logger.Logger.Error("failed to update option", zap.Error(err))
+		return nil, err
+	}
+
+	res := toOption(*opt)
+	return &res, nil
+}
+
+// DeleteOption deletes an option. Values that pick it switch to the
+// replacement option.
+func (s *service) DeleteOption(c context.Context, req DeleteOptionReq, user models.User) (*DeleteOptionRes, error) {
+	fieldDef, err := s.findOptionField(req.ID, user)
+	if err != nil {
+		return nil, err
+	}
+	opt, err := findOption(fieldDef, req.OptionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(fieldDef.Options) == 1 {
+		return nil, fmt.Errorf("a %s field needs at least one option", fieldDef.DataType)
+	}
+
+	var replacement *models.FieldOption
+	if req.ReplacementID != 0 {
+		if req.ReplacementID == opt.ID {
+			return nil, errors.New("an option cannot replace itself")
+		}
+		if replacement, err = findOption(fieldDef, req.ReplacementID); err != nil {
+			return nil, err
+		}
+	}
+
+	values, err := valuesWithOption(s.DB, opt.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) > 0 && replacement == nil {
+		return nil, fmt.Errorf("option %q is used by %d cards, pick a replacement_id", opt.Name, len(values))
+	}
+	usedByDefault := optionInValue(*fieldDef, fieldDef.DefaultValue, opt.Name)
+	if usedByDefault && replacement == nil {
+		return nil, fmt.Errorf("option %q is the default value, pick a replacement_id", opt.Name)
+	}
+	ruleName, err := ruleUsingOption(s.DB, user.ID, *fieldDef, opt.Name)
+	if err != nil {
+		return nil, err
+	}
+	if ruleName != "" && replacement == nil {
+		return nil, fmt.Errorf("option %q is used by automation rule %q, pick a replacement_id", opt.Name, ruleName)
+	}
+	viewName, err := viewUsingOption(s.DB, user.ID, fieldDef.ID, opt.Name)
+	if err != nil {
+		return nil, err
+	}
+	if viewName != "" && replacement == nil {
+		return nil, fmt.Errorf("option %q is used by saved view %q, pick a replacement_id", opt.Name, viewName)
+	}
+
+	remaining := *fieldDef
+	remaining.Options = nil
+	for _, o := range fieldDef.Options {
+		if o.ID != opt.ID {
+			remaining.Options = append(remaining.Options, o)
+		}
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		var changes []models.CardHistory
+		for _, v := range values {
+			before := v.Value
+			value, err := fieldtype.Remap(remaining, v.Value, opt.Name, replacement.Name)
+			if err != nil {
+				return fmt.Errorf("card %d: %w", v.CardID, err)
+			}
+			if err := tx.Model(&v).Update("value", value).Error; err != nil {
+				return err
+			}
+			ids = append(ids, v.ID)
+			change := history.Change(v.CardID, user.ID, models.CardHistoryCustomField, fieldDef.Name, before, value)
+			change.FieldID = fieldDef.ID
+			changes = append(changes, change)
+		}
+
+		if usedByDefault {
+			if err := remapDefault(tx, &remaining, opt.Name, replacement.Name); err != nil {
+				return err
+			}
+		}
+		if ruleName != "" {
+			if err := remapRules(tx, user.ID, remaining, opt.Name, replacement.Name); err != nil {
+				return err
+			}
+		}
+		if viewName != "" {
+			if err := remapViews(tx, user.ID, fieldDef.ID, opt.Name, replacement.Name); err != nil {
+				return err
+			}
+		}
+		if err := tx.Exec("DELETE FROM field_value_options WHERE option_id = ?", opt.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Delete(opt).Error; err != nil {
+			return err
+		}
+		if err := fieldtype.RelinkValues(tx, remaining, ids...); err != nil {
+			return err
+		}
+		return history.Record(tx, changes...)
+	})
+	if err != nil {
+		logger.Logger.Error("failed to delete option", zap.Error(err))
+		return nil, err
+	}
+
+	return &DeleteOptionRes{ReplacedValues: len(values)}, nil
+}
+
+// ReorderOptions sets the display order of a field's options. Multi-select
+// values are rewritten to follow it.
+func (s *service) ReorderOptions(c context.Context, req ReorderOptionsReq, user models.User) (*GetOptionsRes, error) {
+	fieldDef, err := s.findOptionField(req.ID, user)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.OptionIDs) != len(fieldDef.Options) {
+		return nil, errors.New("option_ids must list every option of the field once")
+	}
+	position := map[uint]int{}
+	for i, id := range req.OptionIDs {
+		if _, dup := position[id]; dup {
+			return nil, errors.New("option_ids must list every option of the field once")
+		}
+		position[id] = i
+	}
+	for i := range fieldDef.Options {
+		p, ok := position[fieldDef.Options[i].ID]
+		if !ok {
+			return nil, errors.New("option_ids must list every option of the field once")
+		}
+		fieldDef.Options[i].Position = p
+	}
+	sort.SliceStable(fieldDef.Options, func(i, j int) bool {
+		return fieldDef.Options[i].Position < fieldDef.Options[j].Position
+	})
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		for _, opt := range fieldDef.Options {
+			if err := tx.Model(&opt).Update("position", opt.Position).Error; err != nil {
+				return err
+			}
+		}
+		if fieldtype.DataType(*fieldDef) != models.DataTypeMultiSelect {
+			return nil
+		}
+		if err := remapDefault(tx, fieldDef, "", ""); err != nil {
+			return err
+		}
+
+		var values []models.FieldValue
+		if err := tx.Where("field_id = ? AND value <> ''", fieldDef.ID).Find(&values).Error; err != nil {
+			return err
+		}
+		for _, v := range values {
+			value, err := fieldtype.Remap(*fieldDef, v.Value, "", "")
+			if err != nil {
+				return fmt.Errorf("card %d: %w", v.CardID, err)
+			}
+			if value == v.Value {
+				continue
+			}
+			if err := tx.Model(&v).Update("value", value).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Logger.Error("failed to reorder options", zap.Error(err))
+		return nil, err
+	}
+
+	return s.GetOptions(c, FieldIDReq{ID: fieldDef.ID}, user)
+}
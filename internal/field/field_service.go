@@ -3,14 +3,23 @@ package field
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Cognize-AI/client-cognize/config"
+	"github.com/Cognize-AI/client-cognize/internal/automation"
+	"github.com/Cognize-AI/client-cognize/internal/cardquery"
+	"github.com/Cognize-AI/client-cognize/internal/fieldtype"
+	"github.com/Cognize-AI/client-cognize/internal/history"
 	"github.com/Cognize-AI/client-cognize/logger"
 	"github.com/Cognize-AI/client-cognize/models"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type service struct {
@@ -40,13 +49,36 @@ func (s *service) CreateField(c context.Context, req CreateFieldReq, user models
 	}
 
 	fieldDef = models.FieldDefinition{
-		Name:   req.FieldName,
-		UserID: user.ID,
-		Type:   req.Type,
+		Name:         req.FieldName,
+		UserID:       user.ID,
+		Type:         req.Type,
+		DataType:     req.DataType,
+		Currency:     req.Currency,
+		Options:      toOptions(req.Options),
+		Required:     req.Required,
+		DefaultValue: req.DefaultValue,
+	}
+	if err := fieldtype.Prepare(&fieldDef); err != nil {
+		return nil, err
+	}
+
+	var backfilled int64
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&fieldDef).Error; err != nil {
+			return err
+		}
+		if fieldDef.DefaultValue == "" {
+			return nil
+		}
+		var err error
+		backfilled, err = fieldtype.BackfillDefault(tx, fieldDef)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
-	s.DB.Create(&fieldDef)
 
-	return &CreateFieldRes{fieldDef.ID}, nil
+	return &CreateFieldRes{fieldDef.ID, backfilled}, nil
 }
 
 func (s *service) InsertFieldVal(c context.Context, req InsertFieldValReq, user models.User) (*InsertFieldValRes, error) {
@@ -70,7 +102,7 @@ func (s *service) InsertFieldVal(c context.Context, req InsertFieldValReq, user
 	})
 
 	g.Go(func() error {
-		err := s.DB.
+		err := fieldtype.WithOptions(s.DB).
 			Where("id = ? AND user_id = ?", req.FieldID, user.ID).
 			First(&fieldDef).Error
 
@@ -84,36 +116,98 @@ func (s *service) InsertFieldVal(c context.Context, req InsertFieldValReq, user
 	if err := g.Wait(); err != nil {
 		return nil, err
 	}
+	if fieldDef.Archived {
+		return nil, fmt.Errorf("field %q is archived", fieldDef.Name)
+	}
+
+	value, err := fieldtype.Normalize(fieldDef, req.Value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fieldDef.Name, err)
+	}
+	if value == "" && fieldDef.Required {
+		return nil, fmt.Errorf("%s is required", fieldDef.Name)
+	}
+
+	changed := false
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		var oldValue string
+		tx.Model(&models.FieldValue{}).
+			Where("field_id = ? AND card_id = ?", req.FieldID, req.CardID).
+			Select("value").
+			Scan(&oldValue)
+
+		if err := tx.Where("field_id = ? AND card_id = ?", req.FieldID, req.CardID).
+			Assign(models.FieldValue{
+				CardID:  req.CardID,
+				FieldID: req.FieldID,
+				Value:   value,
+			}).
+			FirstOrCreate(&fieldVal).Error; err != nil {
+			return err
+		}
+		if err := fieldtype.RelinkValues(tx, fieldDef, fieldVal.ID); err != nil {
+			return err
+		}
+		if oldValue == value {
+			return nil
+		}
 
-	s.DB.Where("field_id = ? AND card_id = ?", req.FieldID, req.CardID).
-		Assign(models.FieldValue{
-			CardID:  req.CardID,
-			FieldID: req.FieldID,
-			Value:   req.Value,
-		}).
-		FirstOrCreate(&fieldVal)
+		changed = true
+		change := history.Change(req.CardID, user.ID, models.CardHistoryCustomField, fieldDef.Name, oldValue, value)
+		change.FieldID = fieldDef.ID
+		return history.Record(tx, change)
+	})
+	if err != nil {
+		logger.Logger.Error("Error saving field value", zap.Error(err))
+		return nil, err
+	}
+	if changed {
+		automation.Dispatch(automation.Event{Trigger: models.TriggerFieldChanged, CardID: req.CardID, UserID: user.ID, FieldID: fieldDef.ID})
+	}
 
-	return &InsertFieldValRes{fieldVal.ID}, nil
+	return &InsertFieldValRes{fieldVal.ID, value}, nil
 }
 
-func (s *service) GetFields(c context.Context, user models.User) (*GetFieldsRes, error) {
+func (s *service) GetFields(c context.Context, req GetFieldsReq, user models.User) (*GetFieldsRes, error) {
 	var result []FieldWithSample
 
 	query := `
-        SELECT fd.id, fd.name, fd.type,
+        SELECT fd.id, fd.name, fd.type, fd.data_type, fd.currency, fd.field_order, fd.archived,
+               fd.required, fd.default_value,
                (
                    SELECT fv.value
                    FROM field_values fv
-                   WHERE fv.field_id = fd.id
+                   WHERE fv.field_id = fd.id AND fv.deleted_at IS NULL
                    LIMIT 1
                ) AS sample_value
         FROM field_definitions fd
-        WHERE fd.user_id = ?
+        WHERE fd.user_id = ? AND fd.deleted_at IS NULL AND (? OR NOT fd.archived)
+        ORDER BY fd.type ASC, fd.field_order ASC, fd.id ASC
     `
-	if err := s.DB.Raw(query, user.ID).Scan(&result).Error; err != nil {
+	if err := s.DB.Raw(query, user.ID, req.IncludeArchived).Scan(&result).Error; err != nil {
 		return nil, err
 	}
 
+	var fieldIDs []uint
+	for _, f := range result {
+		if models.FieldDataType(f.DataType).HasOptions() {
+			fieldIDs = append(fieldIDs, f.ID)
+		}
+	}
+	if len(fieldIDs) > 0 {
+		var options []models.FieldOption
+		if err := s.DB.Where("field_id IN ?", fieldIDs).Order("position ASC, id ASC").Find(&options).Error; err != nil {
+			return nil, err
+		}
+		byField := map[uint][]Option{}
+		for _, opt := range options {
+			byField[opt.FieldID] = append(byField[opt.FieldID], toOption(opt))
+		}
+		for i := range result {
+			result[i].Options = byField[result[i].ID]
+		}
+	}
+
 	return &GetFieldsRes{result}, nil
 }
 
@@ -121,20 +215,536 @@ func (s *service) UpdateFieldDefinition(c context.Context, req UpdateFieldDef, u
 	var fieldDef models.FieldDefinition
 	var fieldDef2 models.FieldDefinition
 
-	s.DB.Where("user_id = ? AND id = ?", user.ID, req.ID).First(&fieldDef)
+	fieldtype.WithOptions(s.DB).Where("user_id = ? AND id = ?", user.ID, req.ID).First(&fieldDef)
 	if fieldDef.ID == 0 {
 		logger.Logger.Error("Field definition does not exist")
 		return errors.New("field definition does not exist")
 	}
 
-	s.DB.Where("user_id = ? AND name = ?", user.ID, req.Name).First(&fieldDef2)
-	if fieldDef2.ID != 0 {
-		logger.Logger.Error("Field definition with the same name already exists")
-		return errors.New("field definition with the same name already exists")
+	if req.Name != "" && req.Name != fieldDef.Name {
+		s.DB.Where("user_id = ? AND name = ?", user.ID, req.Name).First(&fieldDef2)
+		if fieldDef2.ID != 0 {
+			logger.Logger.Error("Field definition with the same name already exists")
+			return errors.New("field definition with the same name already exists")
+		}
+		fieldDef.Name = req.Name
+	}
+	if req.Required != nil {
+		fieldDef.Required = *req.Required
+	}
+	backfill := false
+	if req.DefaultValue != nil {
+		value, err := fieldtype.Normalize(fieldDef, *req.DefaultValue)
+		if err != nil {
+			return fmt.Errorf("default_value: %w", err)
+		}
+		backfill = value != "" && value != fieldDef.DefaultValue
+		fieldDef.DefaultValue = value
 	}
 
-	fieldDef.Name = req.Name
-	s.DB.Save(&fieldDef)
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(&fieldDef).Error; err != nil {
+			return err
+		}
+		if !backfill {
+			return nil
+		}
+		_, err := fieldtype.BackfillDefault(tx, fieldDef)
+		return err
+	})
+}
 
+func (s *service) findField(id uint, user models.User) (*models.FieldDefinition, error) {
+	var fieldDef models.FieldDefinition
+	s.DB.Where("id = ? AND user_id = ?", id, user.ID).First(&fieldDef)
+	if fieldDef.ID == 0 {
+		logger.Logger.Error("field not found", zap.String("field_id", strconv.Itoa(int(id))))
+		return nil, errors.New("field definition does not exist")
+	}
+	return &fieldDef, nil
+}
+
+// DeleteField deletes a field with its values, options and list
+// requirements, and removes it from the user's automation rules and saved
+// views.
+func (s *service) DeleteField(c context.Context, req FieldIDReq, user models.User) (*DeleteFieldRes, error) {
+	fieldDef, err := s.findField(req.ID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &DeleteFieldRes{ID: fieldDef.ID}
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM field_value_options WHERE field_id = ?", fieldDef.ID).Error; err != nil {
+			return err
+		}
+		deleted := tx.Where("field_id = ?", fieldDef.ID).Delete(&models.FieldValue{})
+		if deleted.Error != nil {
+			return deleted.Error
+		}
+		res.DeletedValues = deleted.RowsAffected
+
+		if err := tx.Where("field_id = ?", fieldDef.ID).Delete(&models.FieldOption{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("field_id = ?", fieldDef.ID).Delete(&models.ListRequirement{}).Error; err != nil {
+			return err
+		}
+
+		var err error
+		if res.DisabledRules, err = dropFromRules(tx, user.ID, fieldDef.ID); err != nil {
+			return err
+		}
+		if res.UpdatedViews, err = dropFromViews(tx, user.ID, fieldDef.ID); err != nil {
+			return err
+		}
+		return tx.Delete(fieldDef).Error
+	})
+	if err != nil {
+		logger.Logger.Error("failed to delete field", zap.Error(err))
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// dropFromRules removes a deleted field from the user's automation rules.
+// Rules that used it are disabled, since without it they would match more
+// cards than before.
+func dropFromRules(tx *gorm.DB, userID, fieldID uint) (int, error) {
+	var rules []models.AutomationRule
+	if err := tx.Where("user_id = ?", userID).Find(&rules).Error; err != nil {
+		return 0, err
+	}
+
+	disabled := 0
+	for _, rule := range rules {
+		used := rule.TriggerFieldID == fieldID
+		if used {
+			rule.TriggerFieldID = 0
+		}
+		conditions := make([]models.AutomationCondition, 0, len(rule.Conditions))
+		for _, cond := range rule.Conditions {
+			if cond.FieldID == fieldID {
+				used = true
+				continue
+			}
+			conditions = append(conditions, cond)
+		}
+		actions := make([]models.AutomationAction, 0, len(rule.Actions))
+		for _, action := range rule.Actions {
+			if action.FieldID == fieldID {
+				used = true
+				continue
+			}
+			actions = append(actions, action)
+		}
+		if !used {
+			continue
+		}
+
+		rule.Conditions = conditions
+		rule.Actions = actions
+		rule.Enabled = false
+		if err := tx.Omit(clause.Associations).Save(&rule).Error; err != nil {
+			return 0, err
+		}
+		disabled++
+	}
+	return disabled, nil
+}
+
+// dropFromViews removes a deleted field from the columns, filters and sort of
+// the user's saved views.
+func dropFromViews(tx *gorm.DB, userID, fieldID uint) (int, error) {
+	var views []models.SavedView
+	if err := tx.Where("user_id = ?", userID).Find(&views).Error; err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, view := range views {
+		changed := dropFromFilter(&view.Filter, fieldID)
+		if view.Sort.FieldID == fieldID {
+			view.Sort = models.CardSort{}
+			changed = true
+		}
+		columns := make([]models.ViewColumn, 0, len(view.Columns))
+		for _, col := range view.Columns {
+			if col.FieldID == fieldID {
+				changed = true
+				continue
+			}
+			columns = append(columns, col)
+		}
+		if !changed {
+			continue
+		}
+
+		view.Columns = columns
+		if err := tx.Omit(clause.Associations).Save(&view).Error; err != nil {
+			return 0, err
+		}
+		updated++
+	}
+	return updated, nil
+}
+
+func dropFromFilter(filter *models.CardFilter, fieldID uint) bool {
+	changed := false
+	conditions := make([]models.CardCondition, 0, len(filter.Conditions))
+	for _, cond := range filter.Conditions {
+		if cond.FieldID == fieldID {
+			changed = true
+			continue
+		}
+		conditions = append(conditions, cond)
+	}
+	filter.Conditions = conditions
+	for i := range filter.Groups {
+		if dropFromFilter(&filter.Groups[i], fieldID) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// ArchiveField hides a field from cards, or shows it again. Its values are
+// kept.
+func (s *service) ArchiveField(c context.Context, req ArchiveFieldReq, user models.User) error {
+	fieldDef, err := s.findField(req.ID, user)
+	if err != nil {
+		return err
+	}
+	return s.DB.Model(fieldDef).Update("archived", req.Archived).Error
+}
+
+// rebalanceFields renumbers the user's fields of a type 1..n in their current
+// order.
+func rebalanceFields(db *gorm.DB, userID uint, fieldType string) error {
+	var defs []models.FieldDefinition
+	if err := db.
+		Where("user_id = ? AND type = ?", userID, fieldType).
+		Order("field_order ASC, id ASC").
+		Find(&defs).Error; err != nil {
+		return err
+	}
+
+	for i := range defs {
+		if err := db.Model(&defs[i]).Update("field_order", float64(i+1)).Error; err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// MoveField moves a field between two fields of the same type, or to the
+// start or end when one neighbor is missing.
+func (s *service) MoveField(c context.Context, req MoveFieldReq, user models.User) error {
+	if req.CurrField == req.PrevField || req.CurrField == req.NextField {
+		return errors.New("a field cannot be moved next to itself")
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		var currField models.FieldDefinition
+		if err := tx.Where("id = ? AND user_id = ?", req.CurrField, user.ID).First(&currField).Error; err != nil {
+			return fmt.Errorf("current field not found: %w", err)
+		}
+
+		// Rebalance first when orders collide, so neighbors have distinct
+		// positions to insert between.
+		var collisions int64
+		if err := tx.Model(&models.FieldDefinition{}).
+			Where("user_id = ? AND type = ?", user.ID, currField.Type).
+			Select("COUNT(*) - COUNT(DISTINCT field_order)").
+			Scan(&collisions).Error; err != nil {
+			return err
+		}
+		if collisions > 0 {
+			if err := rebalanceFields(tx, user.ID, currField.Type); err != nil {
+				return fmt.Errorf("failed to rebalance fields: %w", err)
+			}
+		}
+
+		// Neighbors must be fields of the same type.
+		load := func(id uint, field *models.FieldDefinition, name string) error {
+			if err := tx.Where("id = ? AND user_id = ? AND type = ?", id, user.ID, currField.Type).First(field).Error; err != nil {
+				return fmt.Errorf("%s field not found: %w", name, err)
+			}
+			return nil
+		}
+		var prevField, nextField models.FieldDefinition
+		if req.PrevField != 0 {
+			if err := load(req.PrevField, &prevField, "previous"); err != nil {
+				return err
+			}
+		}
+		if req.NextField != 0 {
+			if err := load(req.NextField, &nextField, "next"); err != nil {
+				return err
+			}
+		}
+
+		if req.PrevField != 0 && req.NextField != 0 {
+			// Move between two fields
+			if math.Abs(nextField.FieldOrder-prevField.FieldOrder) <= 1e-9 {
+				if err := rebalanceFields(tx, user.ID, currField.Type); err != nil {
+					return fmt.Errorf("failed to rebalance fields: %w", err)
+				}
+				if err := load(req.PrevField, &prevField, "previous"); err != nil {
+					return err
+				}
+				if err := load(req.NextField, &nextField, "next"); err != nil {
+					return err
+				}
+			}
+			currField.FieldOrder = (nextField.FieldOrder + prevField.FieldOrder) / 2
+		} else if req.PrevField == 0 && req.NextField != 0 {
+			// Move to the start
+			currField.FieldOrder = nextField.FieldOrder - 1
+		} else if req.NextField == 0 && req.PrevField != 0 {
+			// Move to the end
+			currField.FieldOrder = prevField.FieldOrder + 1
+		} else {
+			return errors.New("prev_field or next_field is required")
+		}
+
+		if err := tx.Model(&currField).Update("field_order", currField.FieldOrder).Error; err != nil {
+			return fmt.Errorf("failed to update field: %w", err)
+		}
+		return nil
+	})
+}
+
+func toOptions(req []OptionReq) []models.FieldOption {
+	var options []models.FieldOption
+	for _, opt := range req {
+		options = append(options, models.FieldOption{Name: opt.Name, Color: opt.Color})
+	}
+	return options
+}
+
+func toOption(opt models.FieldOption) Option {
+	return Option{ID: opt.ID, Name: opt.Name, Color: opt.Color, Position: opt.Position}
+}
+
+type storedValue struct {
+	ID       uint
+	CardID   uint
+	CardName string
+	Value    string
+}
+
+// optionsFromValues lists the distinct values of a field as options, keeping
+// the field's current options first.
+func optionsFromValues(fieldDef models.FieldDefinition, values []storedValue) []models.FieldOption {
+	options := append([]models.FieldOption{}, fieldDef.Options...)
+	seen := map[string]bool{}
+	for _, opt := range options {
+		seen[strings.ToLower(opt.Name)] = true
+	}
+	for _, v := range values {
+		for _, name := range strings.Split(fieldtype.Display(fieldDef, v.Value), ",") {
+			name = strings.TrimSpace(name)
+			if name == "" || seen[strings.ToLower(name)] {
+				continue
+			}
+			seen[strings.ToLower(name)] = true
+			options = append(options, models.FieldOption{Name: name})
+		}
+	}
+	return options
+}
+
+func (s *service) ConvertField(c context.Context, req ConvertFieldReq, user models.User) (*ConvertFieldRes, error) {
+	var fieldDef models.FieldDefinition
+	fieldtype.WithOptions(s.DB).Where("id = ? AND user_id = ?", req.ID, user.ID).First(&fieldDef)
+	if fieldDef.ID == 0 {
+		logger.Logger.Error("field not found", zap.String("field_id", strconv.Itoa(int(req.ID))))
+		return nil, errors.New("field definition does not exist")
+	}
+
+	var values []storedValue
+	if err := s.DB.Table("field_values").
+		Select("field_values.id, field_values.card_id, cards.name AS card_name, field_values.value").
+		Joins("LEFT JOIN cards ON cards.id = field_values.card_id").
+		Where("field_values.field_id = ? AND field_values.deleted_at IS NULL AND field_values.value <> ''", fieldDef.ID).
+		Order("field_values.id ASC").
+		Scan(&values).Error; err != nil {
+		return nil, err
+	}
+
+	target := models.FieldDefinition{
+		Name:     fieldDef.Name,
+		DataType: req.DataType,
+		Currency: req.Currency,
+		Options:  toOptions(req.Options),
+	}
+	if models.FieldDataType(req.DataType).HasOptions() && len(target.Options) == 0 {
+		target.Options = optionsFromValues(fieldDef, append(values[:len(values):len(values)], storedValue{Value: fieldDef.DefaultValue}))
+	}
+	if err := fieldtype.Prepare(&target); err != nil {
+		return nil, err
+	}
+
+	target.ID = fieldDef.ID
+
+	res := &ConvertFieldRes{
+		FieldID: fieldDef.ID,
+		From:    string(fieldtype.DataType(fieldDef)),
+		To:      target.DataType,
+		Total:   len(values),
+		Failed:  []ConversionFailure{},
+	}
+	var err error
+	if res.IncompatibleViews, err = incompatibleViews(s.DB, user.ID, target); err != nil {
+		return nil, err
+	}
+
+	// A default value that does not convert is reported with card_id 0 and
+	// cleared like the card values. On a required field, values that do not
+	// convert are replaced by the default instead of cleared.
+	defaultValue, defaultErr := fieldtype.Normalize(target, fieldtype.Display(fieldDef, fieldDef.DefaultValue))
+	fill := ""
+	if fieldDef.Required && defaultErr == nil {
+		fill = defaultValue
+	}
+	updates := map[uint]string{}
+	var changes []models.CardHistory
+	for _, v := range values {
+		value, err := fieldtype.Normalize(target, fieldtype.Display(fieldDef, v.Value))
+		if err != nil {
+			res.Failed = append(res.Failed, ConversionFailure{CardID: v.CardID, CardName: v.CardName, Value: v.Value, Error: err.Error()})
+			value = fill
+		} else {
+			res.Converted++
+		}
+		if value == v.Value {
+			continue
+		}
+		if err == nil {
+			res.Changed++
+		}
+		updates[v.ID] = value
+		change := history.Change(v.CardID, user.ID, models.CardHistoryCustomField, fieldDef.Name, v.Value, value)
+		change.FieldID = fieldDef.ID
+		changes = append(changes, change)
+	}
+	cleared := len(res.Failed) > 0 && fill == ""
+	if defaultErr != nil {
+		res.Failed = append(res.Failed, ConversionFailure{Value: fieldDef.DefaultValue, Error: "default_value: " + defaultErr.Error()})
+	}
+	if req.DryRun || len(res.IncompatibleViews) > 0 || (len(res.Failed) > 0 && !req.ClearInvalid) {
+		return res, nil
+	}
+	if fieldDef.Required && cleared {
+		return nil, errors.New("field is required and has no default value for the new type, so invalid values cannot be cleared")
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&fieldDef).Updates(map[string]interface{}{
+			"data_type":     target.DataType,
+			"currency":      target.Currency,
+			"default_value": defaultValue,
+		}).Error; err != nil {
+			return err
+		}
+		if err := syncOptions(tx, fieldDef, target.Options); err != nil {
+			return err
+		}
+		for id, value := range updates {
+			if err := tx.Model(&models.FieldValue{}).Where("id = ?", id).Update("value", value).Error; err != nil {
+				return err
+			}
+		}
+		if err := fieldtype.RelinkField(tx, fieldDef.ID); err != nil {
+			return err
+		}
+		return history.Record(tx, changes...)
+	})
+	if err != nil {
+		logger.Logger.Error("failed to convert field", zap.Error(err))
+		return nil, err
+	}
+
+	res.Applied = true
+	return res, nil
+}
+
+// incompatibleViews lists the user's saved views that filter or sort on the
+// field and no longer compile once it has target's data type and options.
+func incompatibleViews(db *gorm.DB, userID uint, target models.FieldDefinition) ([]IncompatibleView, error) {
+	var views []models.SavedView
+	if err := db.Where("user_id = ?", userID).Order("id ASC").Find(&views).Error; err != nil {
+		return nil, err
+	}
+
+	incompatible := []IncompatibleView{}
+	for _, view := range views {
+		if view.Sort.FieldID != target.ID && !filterUsesField(view.Filter, target.ID) {
+			continue
+		}
+		fields, err := cardquery.LoadFields(db, view.Filter, view.Sort, userID)
+		if err == nil {
+			fields[target.ID] = target
+			if _, _, err = cardquery.Clause(view.Filter, fields); err == nil {
+				_, err = cardquery.Order(db, view.Sort, fields)
+			}
+		}
+		if err != nil {
+			incompatible = append(incompatible, IncompatibleView{ID: view.ID, Name: view.Name, Error: err.Error()})
+		}
+	}
+	return incompatible, nil
+}
+
+// filterUsesField reports whether filter, or one of its groups, has a
+// condition on the field.
+func filterUsesField(filter models.CardFilter, fieldID uint) bool {
+	for _, cond := range filter.Conditions {
+		if cond.FieldID == fieldID {
+			return true
+		}
+	}
+	for _, group := range filter.Groups {
+		if filterUsesField(group, fieldID) {
+			return true
+		}
+	}
+	return false
+}
+
+// syncOptions replaces the options of a field, keeping the ids of options
+// whose name does not change.
+func syncOptions(tx *gorm.DB, fieldDef models.FieldDefinition, options []models.FieldOption) error {
+	existing := map[string]models.FieldOption{}
+	for _, opt := range fieldDef.Options {
+		existing[strings.ToLower(opt.Name)] = opt
+	}
+
+	var keep []uint
+	for _, opt := range options {
+		if old, ok := existing[strings.ToLower(opt.Name)]; ok {
+			keep = append(keep, old.ID)
+			if err := tx.Model(&old).Updates(map[string]interface{}{
+				"name":     opt.Name,
+				"color":    opt.Color,
+				"position": opt.Position,
+			}).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		opt.FieldID = fieldDef.ID
+		if err := tx.Create(&opt).Error; err != nil {
+			return err
+		}
+		keep = append(keep, opt.ID)
+	}
+
+	query := tx.Unscoped().Where("field_id = ?", fieldDef.ID)
+	if len(keep) > 0 {
+		query = query.Where("id NOT IN ?", keep)
+	}
+	return query.Delete(&models.FieldOption{}).Error
+}
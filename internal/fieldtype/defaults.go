@@ -0,0 +1,89 @@
+package fieldtype
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Cognize-AI/client-cognize/models"
+	"gorm.io/gorm"
+)
+
+// Both statements fill the fields fd of the cards c matching a condition
+// with the field's default value: the first adds missing values, the second
+// sets empty ones.
+const (
+	insertDefaultsSQL = `
+	INSERT INTO field_values (created_at, updated_at, card_id, field_id, value)
+	SELECT NOW(), NOW(), c.id, fd.id, fd.default_value
+	FROM cards c
+	JOIN lists l ON l.id = c.list_id
+	JOIN field_definitions fd ON fd.user_id = l.user_id AND fd.deleted_at IS NULL AND NOT fd.archived AND fd.default_value <> ''
+	WHERE c.deleted_at IS NULL AND (%s)
+	  AND NOT EXISTS (SELECT 1 FROM field_values fv WHERE fv.card_id = c.id AND fv.field_id = fd.id AND fv.deleted_at IS NULL)`
+
+	updateDefaultsSQL = `
+	UPDATE field_values SET value = fd.default_value, updated_at = NOW()
+	FROM cards c, field_definitions fd
+	WHERE c.id = field_values.card_id AND fd.id = field_values.field_id
+	  AND fd.deleted_at IS NULL AND NOT fd.archived AND fd.default_value <> ''
+	  AND c.deleted_at IS NULL AND field_values.deleted_at IS NULL AND TRIM(field_values.value) = '' AND (%s)`
+)
+
+func fillDefaults(tx *gorm.DB, cond string, args ...interface{}) (int64, error) {
+	inserted := tx.Exec(fmt.Sprintf(insertDefaultsSQL, cond), args...)
+	if inserted.Error != nil {
+		return 0, inserted.Error
+	}
+	updated := tx.Exec(fmt.Sprintf(updateDefaultsSQL, cond), args...)
+	if updated.Error != nil {
+		return 0, updated.Error
+	}
+	return inserted.RowsAffected + updated.RowsAffected, nil
+}
+
+// FillDefaults gives the cards the default value of every field they leave
+// empty, e.g. after an import.
+func FillDefaults(tx *gorm.DB, cardIDs ...uint) error {
+	if len(cardIDs) == 0 {
+		return nil
+	}
+	if _, err := fillDefaults(tx, "c.id IN ?", cardIDs); err != nil {
+		return err
+	}
+	return RelinkCards(tx, cardIDs...)
+}
+
+// WithDefaults returns values, by field id, with the default value of every
+// active field of the user that values leaves empty, the way FillDefaults
+// would store them for a new card.
+func WithDefaults(db *gorm.DB, userID uint, values map[uint]string) (map[uint]string, error) {
+	var defs []models.FieldDefinition
+	if err := db.Where("user_id = ? AND NOT archived AND default_value <> ''", userID).Find(&defs).Error; err != nil {
+		return nil, err
+	}
+	merged := map[uint]string{}
+	for _, def := range defs {
+		merged[def.ID] = def.DefaultValue
+	}
+	for id, value := range values {
+		if strings.TrimSpace(value) != "" {
+			merged[id] = value
+		}
+	}
+	return merged, nil
+}
+
+// BackfillDefault gives every card of the field's owner that leaves the field
+// empty its default value, and returns how many cards it filled.
+func BackfillDefault(tx *gorm.DB, def models.FieldDefinition) (int64, error) {
+	filled, err := fillDefaults(tx, "fd.id = ?", def.ID)
+	if err != nil {
+		return 0, err
+	}
+	if DataType(def).HasOptions() {
+		if err := RelinkField(tx, def.ID); err != nil {
+			return 0, err
+		}
+	}
+	return filled, nil
+}
@@ -0,0 +1,458 @@
+// Package fieldtype validates, normalizes and decodes custom field values
+// according to the data type of their field definition.
+//
+// Values are stored as text in a canonical form so that they compare and
+// sort in SQL:
+//
+//	number       -1234.5
+//	currency     1234.50, in the field's currency
+//	date         2024-01-15
+//	datetime     2024-01-15T10:30:00Z, always UTC
+//	boolean      true or false
+//	email        jane@example.com
+//	url          https://example.com/pricing
+//	phone        +14155550100, digits with an optional leading +
+//	select       the option name
+//	multi_select a JSON array of option names, in option order
+//
+// An empty value clears the field for every type.
+package fieldtype
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Cognize-AI/client-cognize/models"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultCurrency    = "USD"
+	DefaultOptionColor = "#E5E7EB"
+	MaxOptions         = 200
+
+	minPhoneDigits = 7
+	maxPhoneDigits = 15
+)
+
+var (
+	numberPattern   = regexp.MustCompile(`^[-+]?([0-9]+[.]?[0-9]*|[.][0-9]+)([eE][-+]?[0-9]+)?$`)
+	currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+	currencySymbols = map[string]string{
+		"$": "USD",
+		"€": "EUR",
+		"£": "GBP",
+		"¥": "JPY",
+		"₹": "INR",
+	}
+
+	dateLayouts = []string{
+		"2006-01-02",
+		"2006/01/02",
+		"2 Jan 2006",
+		"Jan 2, 2006",
+		"January 2, 2006",
+	}
+	dateTimeLayouts = []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+		"2006-01-02T15:04",
+		"2006-01-02 15:04",
+	}
+
+	trueValues  = map[string]bool{"true": true, "yes": true, "y": true, "1": true, "on": true}
+	falseValues = map[string]bool{"false": true, "no": true, "n": true, "0": true, "off": true}
+)
+
+// WithOptions preloads the options of the field definitions query loads, in
+// position order.
+func WithOptions(query *gorm.DB) *gorm.DB {
+	return query.Preload("Options", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC, id ASC")
+	})
+}
+
+// DataType returns the definition's data type, string for legacy rows.
+func DataType(def models.FieldDefinition) models.FieldDataType {
+	if def.DataType == "" {
+		return models.DataTypeString
+	}
+	return models.FieldDataType(def.DataType)
+}
+
+// Prepare checks a definition before it is saved: the data type must be
+// known, currency fields get an ISO code and select fields need options.
+// Option names are trimmed and positions renumbered in the given order. The
+// default value is normalized like any stored value.
+func Prepare(def *models.FieldDefinition) error {
+	if err := prepareType(def); err != nil {
+		return err
+	}
+	value, err := Normalize(*def, def.DefaultValue)
+	if err != nil {
+		return fmt.Errorf("default_value: %w", err)
+	}
+	def.DefaultValue = value
+	return nil
+}
+
+func prepareType(def *models.FieldDefinition) error {
+	if def.DataType == "" {
+		def.DataType = string(models.DataTypeString)
+	}
+	dataType := models.FieldDataType(def.DataType)
+	if !dataType.IsValid() {
+		return fmt.Errorf("unknown data_type %q", def.DataType)
+	}
+
+	if dataType == models.DataTypeCurrency {
+		def.Currency = strings.ToUpper(strings.TrimSpace(def.Currency))
+		if def.Currency == "" {
+			def.Currency = DefaultCurrency
+		}
+		if !currencyPattern.MatchString(def.Currency) {
+			return fmt.Errorf("currency must be a three letter ISO code, got %q", def.Currency)
+		}
+	} else if def.Currency != "" {
+		return errors.New("currency is only used by currency fields")
+	}
+
+	if !dataType.HasOptions() {
+		if len(def.Options) > 0 {
+			return fmt.Errorf("%s fields do not have options", dataType)
+		}
+		return nil
+	}
+	if len(def.Options) == 0 {
+		return fmt.Errorf("%s fields need at least one option", dataType)
+	}
+	if len(def.Options) > MaxOptions {
+		return fmt.Errorf("a field can have at most %d options", MaxOptions)
+	}
+	seen := map[string]bool{}
+	for i := range def.Options {
+		opt := &def.Options[i]
+		opt.Name = strings.TrimSpace(opt.Name)
+		if opt.Name == "" {
+			return fmt.Errorf("option %d: name is required", i+1)
+		}
+		key := strings.ToLower(opt.Name)
+		if seen[key] {
+			return fmt.Errorf("option %q is listed twice", opt.Name)
+		}
+		seen[key] = true
+		if opt.Color == "" {
+			opt.Color = DefaultOptionColor
+		}
+		opt.Position = i
+	}
+	return nil
+}
+
+// Normalize validates raw against the definition and returns the value to
+// store. Select fields need def.Options loaded.
+func Normalize(def models.FieldDefinition, raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+
+	switch DataType(def) {
+	case models.DataTypeString:
+		return raw, nil
+	case models.DataTypeNumber:
+		n, err := parseNumber(raw)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatFloat(n, 'f', -1, 64), nil
+	case models.DataTypeCurrency:
+		return normalizeCurrency(def, raw)
+	case models.DataTypeDate:
+		t, err := parseDate(raw)
+		if err != nil {
+			return "", err
+		}
+		return t.Format("2006-01-02"), nil
+	case models.DataTypeDateTime:
+		t, err := parseDateTime(raw)
+		if err != nil {
+			return "", err
+		}
+		return t.UTC().Format(time.RFC3339), nil
+	case models.DataTypeBoolean:
+		v := strings.ToLower(raw)
+		switch {
+		case trueValues[v]:
+			return "true", nil
+		case falseValues[v]:
+			return "false", nil
+		}
+		return "", fmt.Errorf("%q is not a boolean", raw)
+	case models.DataTypeEmail:
+		return normalizeEmail(raw)
+	case models.DataTypeURL:
+		return normalizeURL(raw)
+	case models.DataTypePhone:
+		return normalizePhone(raw)
+	case models.DataTypeSelect:
+		opt, ok := findOption(def, raw)
+		if !ok {
+			return "", fmt.Errorf("%q is not an option of %s", raw, def.Name)
+		}
+		return opt.Name, nil
+	case models.DataTypeMultiSelect:
+		return normalizeMultiSelect(def, raw)
+	}
+	return "", fmt.Errorf("unknown data_type %q", def.DataType)
+}
+
+// Typed decodes a stored value for API responses: numbers and currency
+// amounts as numbers, booleans as booleans, multi_select as a list of option
+// names and everything else as a string. Empty values are nil.
+func Typed(def models.FieldDefinition, value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	switch DataType(def) {
+	case models.DataTypeNumber, models.DataTypeCurrency:
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	case models.DataTypeBoolean:
+		return value == "true"
+	case models.DataTypeMultiSelect:
+		if names, err := decodeList(value); err == nil {
+			return names
+		}
+	}
+	return value
+}
+
+// Display renders a stored value as plain text, the form used as input when
+// a field changes type.
+func Display(def models.FieldDefinition, value string) string {
+	if DataType(def) == models.DataTypeMultiSelect {
+		if names, err := decodeList(value); err == nil {
+			return strings.Join(names, ", ")
+		}
+	}
+	return value
+}
+
+func parseNumber(raw string) (float64, error) {
+	cleaned := strings.NewReplacer(",", "", " ", "", "_", "").Replace(raw)
+	if !numberPattern.MatchString(cleaned) {
+		return 0, fmt.Errorf("%q is not a number", raw)
+	}
+	n, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+		return 0, fmt.Errorf("%q is not a number", raw)
+	}
+	return n, nil
+}
+
+// normalizeCurrency accepts an amount with an optional currency symbol or
+// code, which must match the field's currency.
+func normalizeCurrency(def models.FieldDefinition, raw string) (string, error) {
+	currency := def.Currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+
+	amount := raw
+	var given string
+	for symbol, code := range currencySymbols {
+		if strings.Contains(amount, symbol) {
+			given = code
+			amount = strings.Replace(amount, symbol, "", 1)
+			break
+		}
+	}
+	if given == "" {
+		if fields := strings.Fields(amount); len(fields) == 2 {
+			for i, f := range fields {
+				if currencyPattern.MatchString(strings.ToUpper(f)) {
+					given = strings.ToUpper(f)
+					amount = fields[1-i]
+					break
+				}
+			}
+		}
+	}
+	if given != "" && given != currency {
+		return "", fmt.Errorf("%q is not in %s", raw, currency)
+	}
+
+	n, err := parseNumber(strings.TrimSpace(amount))
+	if err != nil {
+		return "", fmt.Errorf("%q is not an amount", raw)
+	}
+	return strconv.FormatFloat(n, 'f', 2, 64), nil
+}
+
+func parseDate(raw string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.Parse(time.RFC3339Nano, raw); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date, use YYYY-MM-DD", raw)
+}
+
+// parseDateTime reads times without an offset as UTC.
+func parseDateTime(raw string) (time.Time, error) {
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := parseDate(raw); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date and time, use RFC 3339", raw)
+}
+
+func normalizeEmail(raw string) (string, error) {
+	addr, err := mail.ParseAddress(raw)
+	if err != nil {
+		return "", fmt.Errorf("%q is not an email address", raw)
+	}
+	at := strings.LastIndex(addr.Address, "@")
+	if at < 1 || !strings.Contains(addr.Address[at:], ".") {
+		return "", fmt.Errorf("%q is not an email address", raw)
+	}
+	return addr.Address[:at] + strings.ToLower(addr.Address[at:]), nil
+}
+
+func normalizeURL(raw string) (string, error) {
+	full := raw
+	if !strings.Contains(full, "://") {
+		full = "https://" + full
+	}
+	u, err := url.Parse(full)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || !strings.Contains(u.Hostname(), ".") {
+		return "", fmt.Errorf("%q is not a URL", raw)
+	}
+	u.Host = strings.ToLower(u.Host)
+	return u.String(), nil
+}
+
+func normalizePhone(raw string) (string, error) {
+	var b strings.Builder
+	for i, r := range raw {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		case strings.ContainsRune(" -.()/", r):
+		default:
+			return "", fmt.Errorf("%q is not a phone number", raw)
+		}
+	}
+	phone := b.String()
+	digits := len(strings.TrimPrefix(phone, "+"))
+	if digits < minPhoneDigits || digits > maxPhoneDigits {
+		return "", fmt.Errorf("%q is not a phone number", raw)
+	}
+	return phone, nil
+}
+
+func findOption(def models.FieldDefinition, name string) (models.FieldOption, bool) {
+	for _, opt := range def.Options {
+		if strings.EqualFold(opt.Name, name) {
+			return opt, true
+		}
+	}
+	return models.FieldOption{}, false
+}
+
+// normalizeMultiSelect accepts a JSON array or a comma-separated list.
+func normalizeMultiSelect(def models.FieldDefinition, raw string) (string, error) {
+	names, err := decodeList(raw)
+	if err != nil {
+		names = strings.Split(raw, ",")
+	}
+
+	seen := map[string]bool{}
+	var picked []models.FieldOption
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		opt, ok := findOption(def, name)
+		if !ok {
+			return "", fmt.Errorf("%q is not an option of %s", name, def.Name)
+		}
+		if key := strings.ToLower(opt.Name); !seen[key] {
+			seen[key] = true
+			picked = append(picked, opt)
+		}
+	}
+	if len(picked) == 0 {
+		return "", nil
+	}
+
+	sort.SliceStable(picked, func(i, j int) bool {
+		return picked[i].Position < picked[j].Position
+	})
+	result := make([]string, 0, len(picked))
+	for _, opt := range picked {
+		result = append(result, opt.Name)
+	}
+	b, err := json.Marshal(result)
+	return string(b), err
+}
+
+func decodeList(value string) ([]string, error) {
+	if !strings.HasPrefix(value, "[") {
+		return nil, errors.New("not a list")
+	}
+	var names []string
+	err := json.Unmarshal([]byte(value), &names)
+	return names, err
+}
+
+// Remap replaces option from with option to in a stored value and
+// normalizes the result against def, whose options must already reflect the
+// change. With an empty from it only normalizes, which puts multi_select
+// values back in option order.
+func Remap(def models.FieldDefinition, value, from, to string) (string, error) {
+	multi := DataType(def) == models.DataTypeMultiSelect
+	names := []string{value}
+	if multi {
+		var err error
+		if names, err = decodeList(value); err != nil {
+			return "", err
+		}
+	}
+	for i, name := range names {
+		if from != "" && strings.EqualFold(name, from) {
+			names[i] = to
+		}
+	}
+	if !multi {
+		return Normalize(def, names[0])
+	}
+	b, err := json.Marshal(names)
+	if err != nil {
+		return "", err
+	}
+	return Normalize(def, string(b))
+}
@@ -0,0 +1,89 @@
+package fieldtype
+
+import (
+	"testing"
+
+	"github.com/Cognize-AI/client-cognize/models"
+)
+
+func TestNormalize(t *testing.T) {
+	def := func(dataType models.FieldDataType) models.FieldDefinition {
+		return models.FieldDefinition{Name: "Field", DataType: string(dataType)}
+	}
+	eur := models.FieldDefinition{Name: "Price", DataType: string(models.DataTypeCurrency), Currency: "EUR"}
+	options := []models.FieldOption{{Name: "SaaS", Position: 0}, {Name: "Fintech", Position: 1}, {Name: "Retail", Position: 2}}
+	single := models.FieldDefinition{Name: "Industry", DataType: string(models.DataTypeSelect), Options: options}
+	multi := models.FieldDefinition{Name: "Industries", DataType: string(models.DataTypeMultiSelect), Options: options}
+
+	tests := []struct {
+		name    string
+		def     models.FieldDefinition
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{"empty clears", def(models.DataTypeNumber), "   ", "", false},
+		{"legacy string", models.FieldDefinition{}, " anything ", "anything", false},
+		{"string", def(models.DataTypeString), "=1+1", "=1+1", false},
+
+		{"number", def(models.DataTypeNumber), "1,234.50", "1234.5", false},
+		{"negative number", def(models.DataTypeNumber), "-0.25", "-0.25", false},
+		{"exponent", def(models.DataTypeNumber), "1e3", "1000", false},
+		{"not a number", def(models.DataTypeNumber), "12abc", "", true},
+		{"infinity", def(models.DataTypeNumber), "1e999", "", true},
+
+		{"currency default", def(models.DataTypeCurrency), "$1,234.5", "1234.50", false},
+		{"currency code", eur, "5 eur", "5.00", false},
+		{"currency symbol", eur, "€7", "7.00", false},
+		{"wrong currency", eur, "$7", "", true},
+		{"wrong currency code", def(models.DataTypeCurrency), "EUR 7", "", true},
+		{"not an amount", def(models.DataTypeCurrency), "lots", "", true},
+
+		{"date", def(models.DataTypeDate), "2024-01-15", "2024-01-15", false},
+		{"date long form", def(models.DataTypeDate), "January 5, 2024", "2024-01-05", false},
+		{"date from timestamp", def(models.DataTypeDate), "2024-01-15T23:30:00+02:00", "2024-01-15", false},
+		{"not a date", def(models.DataTypeDate), "sometime", "", true},
+
+		{"datetime offset to utc", def(models.DataTypeDateTime), "2024-01-15T10:30:00+02:00", "2024-01-15T08:30:00Z", false},
+		{"datetime without offset", def(models.DataTypeDateTime), "2024-01-15 10:30", "2024-01-15T10:30:00Z", false},
+		{"datetime from date", def(models.DataTypeDateTime), "2024-01-15", "2024-01-15T00:00:00Z", false},
+		{"not a datetime", def(models.DataTypeDateTime), "noon", "", true},
+
+		{"boolean yes", def(models.DataTypeBoolean), "Yes", "true", false},
+		{"boolean off", def(models.DataTypeBoolean), "OFF", "false", false},
+		{"not a boolean", def(models.DataTypeBoolean), "maybe", "", true},
+
+		{"email", def(models.DataTypeEmail), "Jane <Jane.Doe@Example.COM>", "Jane.Doe@example.com", false},
+		{"email without dot", def(models.DataTypeEmail), "jane@localhost", "", true},
+		{"not an email", def(models.DataTypeEmail), "jane", "", true},
+
+		{"url without scheme", def(models.DataTypeURL), "Example.com/Pricing", "https://example.com/Pricing", false},
+		{"http url", def(models.DataTypeURL), "http://example.com", "http://example.com", false},
+		{"ftp url", def(models.DataTypeURL), "ftp://example.com", "", true},
+		{"url without dot", def(models.DataTypeURL), "intranet", "", true},
+
+		{"phone", def(models.DataTypePhone), "+1 (415) 555-0100", "+14155550100", false},
+		{"phone too short", def(models.DataTypePhone), "12345", "", true},
+		{"phone with letters", def(models.DataTypePhone), "555-CALL-NOW", "", true},
+		{"phone plus inside", def(models.DataTypePhone), "1+4155550100", "", true},
+
+		{"select ignores case", single, "saas", "SaaS", false},
+		{"unknown option", single, "Gaming", "", true},
+		{"multi select list", multi, "Retail, saas, Retail", `["SaaS","Retail"]`, false},
+		{"multi select json", multi, `["Fintech","SaaS"]`, `["SaaS","Fintech"]`, false},
+		{"multi select empty items", multi, " , ", "", false},
+		{"multi select unknown", multi, "SaaS, Gaming", "", true},
+
+		{"unknown type", def("color"), "red", "", true},
+	}
+	for _, tt := range tests {
+		got, err := Normalize(tt.def, tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Normalize(%q) error = %v, want error %v", tt.name, tt.raw, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: Normalize(%q) = %q, want %q", tt.name, tt.raw, got, tt.want)
+		}
+	}
+}
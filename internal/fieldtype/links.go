@@ -0,0 +1,70 @@
+package fieldtype
+
+import (
+	"fmt"
+
+	"github.com/Cognize-AI/client-cognize/models"
+	"gorm.io/gorm"
+)
+
+// linkSQL links the select and multi_select values matching the condition on
+// fv to the options they pick.
+const linkSQL = `
+	INSERT INTO field_value_options (field_value_id, option_id, field_id)
+	SELECT fv.id, fo.id, fv.field_id
+	FROM field_values fv
+	JOIN field_definitions fd ON fd.id = fv.field_id AND fd.data_type IN ('select', 'multi_select')
+	JOIN field_options fo ON fo.field_id = fv.field_id AND fo.deleted_at IS NULL
+	WHERE fv.deleted_at IS NULL AND fv.value <> '' AND (%s)
+	  AND CASE WHEN fd.data_type = 'multi_select'
+	      THEN fv.value::jsonb @> jsonb_build_array(fo.name)
+	      ELSE fv.value = fo.name END
+	ON CONFLICT DO NOTHING`
+
+func relink(tx *gorm.DB, cond string, args ...interface{}) error {
+	if err := tx.Exec(
+		fmt.Sprintf("DELETE FROM field_value_options WHERE field_value_id IN (SELECT fv.id FROM field_values fv WHERE %s)", cond),
+		args...,
+	).Error; err != nil {
+		return err
+	}
+	return tx.Exec(fmt.Sprintf(linkSQL, cond), args...).Error
+}
+
+// RelinkValues refreshes the option links of values of def after they are
+// written. It does nothing for fields without options.
+func RelinkValues(tx *gorm.DB, def models.FieldDefinition, fieldValueIDs ...uint) error {
+	if len(fieldValueIDs) == 0 || !DataType(def).HasOptions() {
+		return nil
+	}
+	return relink(tx, "fv.id IN ?", fieldValueIDs)
+}
+
+// RelinkCards refreshes the option links of every value of the cards.
+func RelinkCards(tx *gorm.DB, cardIDs ...uint) error {
+	if len(cardIDs) == 0 {
+		return nil
+	}
+	return relink(tx, "fv.card_id IN ?", cardIDs)
+}
+
+// RelinkField refreshes the option links of every value of a field, e.g.
+// after its type or options change.
+func RelinkField(tx *gorm.DB, fieldID uint) error {
+	return relink(tx, "fv.field_id = ?", fieldID)
+}
+
+// LinkMissing links the values that have no option links yet. It backfills
+// values written before links were kept.
+func LinkMissing(db *gorm.DB) error {
+	return db.Exec(fmt.Sprintf(linkSQL, "NOT EXISTS (SELECT 1 FROM field_value_options l WHERE l.field_value_id = fv.id)")).Error
+}
+
+// UnlinkCards drops the option links of the cards' values before the values
+// are purged.
+func UnlinkCards(tx *gorm.DB, cardIDs ...uint) error {
+	if len(cardIDs) == 0 {
+		return nil
+	}
+	return tx.Exec("DELETE FROM field_value_options WHERE field_value_id IN (SELECT id FROM field_values WHERE card_id IN ?)", cardIDs).Error
+}
@@ -0,0 +1,41 @@
+package history
+
+import (
+	"context"
+	"time"
+
+	"github.com/Cognize-AI/client-cognize/models"
+)
+
+type GetCardHistoryReq struct {
+	ID     uint `uri:"id" binding:"required"`
+	Before uint `form:"before"`
+	Limit  int  `form:"limit"`
+}
+
+type Actor struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type Entry struct {
+	ID        uint      `json:"id"`
+	Kind      string    `json:"kind"`
+	Field     string    `json:"field"`
+	FieldID   uint      `json:"field_id,omitempty"`
+	OldValue  string    `json:"old_value"`
+	NewValue  string    `json:"new_value"`
+	Actor     Actor     `json:"actor"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type GetCardHistoryResp struct {
+	CardID  uint    `json:"card_id"`
+	Entries []Entry `json:"entries"`
+	// NextBefore is passed as ?before= to fetch older entries, 0 when done.
+	NextBefore uint `json:"next_before"`
+}
+
+type Service interface {
+	GetCardHistory(ctx context.Context, req GetCardHistoryReq, user models.User) (*GetCardHistoryResp, error)
+}
@@ -0,0 +1,99 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Cognize-AI/client-cognize/config"
+	"github.com/Cognize-AI/client-cognize/logger"
+	"github.com/Cognize-AI/client-cognize/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 500
+)
+
+type service struct {
+	timeout time.Duration
+	DB      *gorm.DB
+}
+
+func NewService() Service {
+	return &service{
+		time.Duration(20) * time.Second,
+		config.DB,
+	}
+}
+
+// Record stores card changes. Pass the transaction that writes the change so
+// that history and data stay consistent.
+func Record(db *gorm.DB, entries ...models.CardHistory) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return db.Create(&entries).Error
+}
+
+// Change builds a history entry.
+func Change(cardID, actorID uint, kind models.CardHistoryKind, field, oldValue, newValue string) models.CardHistory {
+	return models.CardHistory{
+		CardID:   cardID,
+		ActorID:  actorID,
+		Kind:     string(kind),
+		Field:    field,
+		OldValue: oldValue,
+		NewValue: newValue,
+	}
+}
+
+func (s *service) GetCardHistory(ctx context.Context, req GetCardHistoryReq, user models.User) (*GetCardHistoryResp, error) {
+	var card models.Card
+	s.DB.Preload("List").Where("id = ?", req.ID).First(&card)
+	if card.ID == 0 || card.List.UserID != user.ID {
+		logger.Logger.Error("card_id not found for card_id: ", zap.String("card_id", strconv.Itoa(int(req.ID))))
+		return nil, errors.New("card_id not found for card_id: " + strconv.Itoa(int(req.ID)))
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	query := s.DB.Preload("Actor").Where("card_id = ?", card.ID)
+	if req.Before != 0 {
+		query = query.Where("id < ?", req.Before)
+	}
+
+	var rows []models.CardHistory
+	if err := query.Order("id DESC").Limit(limit + 1).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	res := &GetCardHistoryResp{CardID: card.ID, Entries: []Entry{}}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		res.NextBefore = rows[len(rows)-1].ID
+	}
+	for _, row := range rows {
+		res.Entries = append(res.Entries, Entry{
+			ID:        row.ID,
+			Kind:      row.Kind,
+			Field:     row.Field,
+			FieldID:   row.FieldID,
+			OldValue:  row.OldValue,
+			NewValue:  row.NewValue,
+			Actor:     Actor{ID: row.Actor.ID, Name: row.Actor.Name},
+			CreatedAt: row.CreatedAt,
+		})
+	}
+
+	return res, nil
+}
@@ -10,9 +10,11 @@ import (
 
 type GetListResponse struct {
 	ID        uint      `json:"id"`
+	BoardID   uint      `json:"board_id"`
 	Name      string    `json:"name"`
 	Color     string    `json:"color"`
 	ListOrder float64   `json:"list_order"`
+	WIPLimit  int       `json:"wip_limit"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -27,15 +29,149 @@ type CardListResponse struct {
 	Cards     []card.GetCard `json:"cards"`
 }
 
+type CreateDefaultListsReq struct {
+	// Template is a built-in pipeline template and TemplateID a saved one.
+	// The sales template is used when neither is set.
+	Template   string `form:"template"`
+	TemplateID uint   `form:"template_id"`
+}
+
 type CreateDefaultListsRes struct {
 	Lists []GetListResponse `json:"lists"`
 }
 
+type GetListsReq struct {
+	// BoardID selects the board, the user's default board when zero.
+	BoardID uint `form:"board_id"`
+	// TagsAny, TagsAll and TagsNone keep only the cards with at least one,
+	// all or none of the tags. They are repeated query parameters.
+	TagsAny  []uint `form:"tags_any"`
+	TagsAll  []uint `form:"tags_all"`
+	TagsNone []uint `form:"tags_none"`
+}
+
 type GetListsRes struct {
-	Lists []CardListResponse `json:"lists"`
+	BoardID uint               `json:"board_id"`
+	Lists   []CardListResponse `json:"lists"`
+}
+
+type GetListCardsReq struct {
+	ID     uint   `uri:"id" binding:"required"`
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+	Sort   string `form:"sort"`
+}
+
+type GetListCardsRes struct {
+	ListID     uint           `json:"list_id"`
+	Sort       string         `json:"sort"`
+	Cards      []card.GetCard `json:"cards"`
+	NextCursor string         `json:"next_cursor"`
+	HasMore    bool           `json:"has_more"`
+}
+
+type ListSummary struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	ListOrder float64   `json:"list_order"`
+	WIPLimit  int       `json:"wip_limit"`
+	CardCount int64     `json:"card_count"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type BoardSummaryRes struct {
+	BoardID uint          `json:"board_id"`
+	Lists   []ListSummary `json:"lists"`
+}
+
+type TrashList struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	CardCount int64     `json:"card_count"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type GetTrashRes struct {
+	Lists []TrashList `json:"lists"`
+}
+
+type ListIDReq struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+type CreateListReq struct {
+	// BoardID is the board to add the list to, the default board when zero.
+	BoardID uint   `json:"board_id"`
+	Name    string `json:"name" binding:"required"`
+	Color   string `json:"color"`
+}
+
+type UpdateListReq struct {
+	ID uint `uri:"id" binding:"required"`
+	// Name and Color are left unchanged when empty.
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type DeleteListReq struct {
+	ID uint `uri:"id" binding:"required"`
+	// TargetListID receives the list's cards. Without it Cascade must be set,
+	// which moves the cards to the trash together with the list.
+	TargetListID uint `form:"target_list_id"`
+	Cascade      bool `form:"cascade"`
+}
+
+type DeleteListRes struct {
+	ID           uint `json:"id"`
+	MovedCards   int  `json:"moved_cards"`
+	TrashedCards int  `json:"trashed_cards"`
+}
+
+type MoveListReq struct {
+	PrevList uint `json:"prev_list"`
+	CurrList uint `json:"curr_list" binding:"required"`
+	NextList uint `json:"next_list"`
+}
+
+// RequiredField is either a built-in card field (Field) or a custom field
+// (FieldID).
+type RequiredField struct {
+	Field   string `json:"field,omitempty"`
+	FieldID uint   `json:"field_id,omitempty"`
+	Name    string `json:"name"`
+}
+
+type UpdateListRulesReq struct {
+	ID uint `uri:"id" binding:"required"`
+	// WIPLimit is the maximum number of cards, 0 for no limit.
+	WIPLimit int `json:"wip_limit"`
+	// RequiredFields are built-in card fields such as "email".
+	RequiredFields []string `json:"required_fields"`
+	// RequiredFieldIDs are custom field definitions.
+	RequiredFieldIDs []uint `json:"required_field_ids"`
+}
+
+type ListRulesRes struct {
+	ListID         uint            `json:"list_id"`
+	WIPLimit       int             `json:"wip_limit"`
+	RequiredFields []RequiredField `json:"required_fields"`
 }
 
 type Service interface {
-	CreateDefaultLists(c context.Context, user models.User) (*CreateDefaultListsRes, error)
-	GetLists(c context.Context, user models.User) (*GetListsRes, error)
+	CreateDefaultLists(c context.Context, req CreateDefaultListsReq, user models.User) (*CreateDefaultListsRes, error)
+	GetLists(c context.Context, req GetListsReq, user models.User) (*GetListsRes, error)
+	CreateList(c context.Context, req CreateListReq, user models.User) (*GetListResponse, error)
+	UpdateList(c context.Context, req UpdateListReq, user models.User) (*GetListResponse, error)
+	DeleteList(c context.Context, req DeleteListReq, user models.User) (*DeleteListRes, error)
+	MoveList(c context.Context, req MoveListReq, user models.User) error
+	GetListRules(c context.Context, req ListIDReq, user models.User) (*ListRulesRes, error)
+	UpdateListRules(c context.Context, req UpdateListRulesReq, user models.User) (*ListRulesRes, error)
+	GetListCards(c context.Context, req GetListCardsReq, user models.User) (*GetListCardsRes, error)
+	GetBoardSummary(c context.Context, req GetListsReq, user models.User) (*BoardSummaryRes, error)
+	GetTrash(c context.Context, user models.User) (*GetTrashRes, error)
+	RestoreList(c context.Context, req ListIDReq, user models.User) error
+	DeleteListPermanently(c context.Context, req ListIDReq, user models.User) error
 }
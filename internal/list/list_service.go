@@ -2,15 +2,29 @@ package list
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Cognize-AI/client-cognize/config"
+	"github.com/Cognize-AI/client-cognize/internal/analytics"
+	"github.com/Cognize-AI/client-cognize/internal/automation"
+	"github.com/Cognize-AI/client-cognize/internal/board"
 	"github.com/Cognize-AI/client-cognize/internal/card"
+	"github.com/Cognize-AI/client-cognize/internal/cardquery"
+	"github.com/Cognize-AI/client-cognize/internal/history"
+	"github.com/Cognize-AI/client-cognize/internal/pipeline"
+	"github.com/Cognize-AI/client-cognize/internal/stagerules"
 	"github.com/Cognize-AI/client-cognize/internal/tag"
 	"github.com/Cognize-AI/client-cognize/logger"
 	"github.com/Cognize-AI/client-cognize/models"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
@@ -26,84 +40,442 @@ func NewService() Service {
 	}
 }
 
-func (s *service) CreateDefaultLists(c context.Context, user models.User) (*CreateDefaultListsRes, error) {
+func (s *service) CreateDefaultLists(c context.Context, req CreateDefaultListsReq, user models.User) (*CreateDefaultListsRes, error) {
 	var lists []models.List
 	var resLists []GetListResponse
 
-	s.DB.Where("user_id = ?", user.ID).Find(&lists)
+	template, err := pipeline.Resolve(s.DB, req.Template, req.TemplateID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	_board, err := board.DefaultBoard(s.DB, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.DB.Where("board_id = ?", _board.ID).Find(&lists)
 	if len(lists) > 0 {
 		return nil, errors.New("default lists already exists")
 	}
 
-	lists = append(lists, models.List{
-		Name:   "New Leads",
-		Color:  "#F9BA0B",
-		UserID: user.ID,
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		lists, err = pipeline.Apply(tx, *template, user.ID, _board.ID)
+		return err
 	})
-	lists = append(lists, models.List{
-		Name:   "Signed In",
-		Color:  "#40C2FC",
-		UserID: user.ID,
-	})
-	lists = append(lists, models.List{
-		Name:   "Qualified",
-		Color:  "#75C699",
-		UserID: user.ID,
+	if err != nil {
+		logger.Logger.Error("failed to create default lists", zap.Error(err))
+		return nil, err
+	}
+
+	for _, list := range lists {
+		resLists = append(resLists, *toListResponse(list))
+	}
+	logger.Logger.Info("Created default lists")
+	return &CreateDefaultListsRes{Lists: resLists}, nil
+}
+
+func toListResponse(list models.List) *GetListResponse {
+	return &GetListResponse{
+		ID:        list.ID,
+		BoardID:   list.BoardID,
+		Name:      list.Name,
+		Color:     list.Color,
+		ListOrder: list.ListOrder,
+		WIPLimit:  list.WIPLimit,
+		CreatedAt: list.CreatedAt,
+		UpdatedAt: list.UpdatedAt,
+	}
+}
+
+func (s *service) findList(id uint, user models.User) (*models.List, error) {
+	var list models.List
+	s.DB.Where("id = ? AND user_id = ?", id, user.ID).First(&list)
+	if list.ID == 0 {
+		logger.Logger.Error("list not found", zap.String("list_id", strconv.Itoa(int(id))))
+		return nil, errors.New("list not found")
+	}
+	return &list, nil
+}
+
+func (s *service) CreateList(c context.Context, req CreateListReq, user models.User) (*GetListResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("list name is required")
+	}
+
+	_board, err := board.FindBoard(s.DB, req.BoardID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var maxOrder float64
+	s.DB.Model(&models.List{}).Where("board_id = ?", _board.ID).Select("COALESCE(MAX(list_order), 0)").Scan(&maxOrder)
+
+	list := models.List{
+		Name:      name,
+		Color:     req.Color,
+		UserID:    user.ID,
+		BoardID:   _board.ID,
+		ListOrder: maxOrder + 1,
+	}
+	if err := s.DB.Create(&list).Error; err != nil {
+		logger.Logger.Error("failed to create list", zap.Error(err))
+		return nil, fmt.Errorf("failed to create list: %w", err)
+	}
+
+	return toListResponse(list), nil
+}
+
+func (s *service) UpdateList(c context.Context, req UpdateListReq, user models.User) (*GetListResponse, error) {
+	list, err := s.findList(req.ID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		list.Name = name
+	}
+	if req.Color != "" {
+		list.Color = req.Color
+	}
+	if err := s.DB.Save(list).Error; err != nil {
+		logger.Logger.Error("failed to update list", zap.Error(err))
+		return nil, fmt.Errorf("failed to update list: %w", err)
+	}
+
+	return toListResponse(*list), nil
+}
+
+// RebalanceLists renumbers a board's lists 1..n in their current order. Lists
+// created before ordering existed all share order 0 and keep creation order.
+func RebalanceLists(db *gorm.DB, boardID uint) error {
+	var lists []models.List
+	if err := db.
+		Where("board_id = ?", boardID).
+		Order("list_order ASC, id ASC").
+		Find(&lists).Error; err != nil {
+		return err
+	}
+
+	for i := range lists {
+		lists[i].ListOrder = float64(i + 1)
+	}
+
+	return db.Save(&lists).Error
+}
+
+func (s *service) MoveList(c context.Context, req MoveListReq, user models.User) error {
+	if req.CurrList == req.PrevList || req.CurrList == req.NextList {
+		return errors.New("a list cannot be moved next to itself")
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		var currList models.List
+		if err := tx.Where("id = ? AND user_id = ?", req.CurrList, user.ID).First(&currList).Error; err != nil {
+			return fmt.Errorf("current list not found: %w", err)
+		}
+
+		// Rebalance first when orders collide, so neighbors have distinct
+		// positions to insert between.
+		var collisions int64
+		if err := tx.Model(&models.List{}).
+			Where("board_id = ?", currList.BoardID).
+			Select("COUNT(*) - COUNT(DISTINCT list_order)").
+			Scan(&collisions).Error; err != nil {
+			return err
+		}
+		if collisions > 0 {
+			if err := RebalanceLists(tx, currList.BoardID); err != nil {
+				return fmt.Errorf("failed to rebalance lists: %w", err)
+			}
+		}
+
+		// Neighbors must be on the same board as the moved list.
+		load := func(id uint, list *models.List, name string) error {
+			if err := tx.Where("id = ? AND board_id = ?", id, currList.BoardID).First(list).Error; err != nil {
+				return fmt.Errorf("%s list not found: %w", name, err)
+			}
+			return nil
+		}
+
+		var prevList, nextList models.List
+		if req.PrevList != 0 {
+			if err := load(req.PrevList, &prevList, "previous"); err != nil {
+				return err
+			}
+		}
+		if req.NextList != 0 {
+			if err := load(req.NextList, &nextList, "next"); err != nil {
+				return err
+			}
+		}
+
+		if req.PrevList != 0 && req.NextList != 0 {
+			// Move between two lists
+			if math.Abs(nextList.ListOrder-prevList.ListOrder) <= 1e-9 {
+				if err := RebalanceLists(tx, currList.BoardID); err != nil {
+					return fmt.Errorf("failed to rebalance lists: %w", err)
+				}
+				if err := load(req.PrevList, &prevList, "previous"); err != nil {
+					return err
+				}
+				if err := load(req.NextList, &nextList, "next"); err != nil {
+					return err
+				}
+			}
+			currList.ListOrder = (nextList.ListOrder + prevList.ListOrder) / 2
+		} else if req.PrevList == 0 && req.NextList != 0 {
+			// Move to the start
+			currList.ListOrder = nextList.ListOrder - 1
+		} else if req.NextList == 0 && req.PrevList != 0 {
+			// Move to the end
+			currList.ListOrder = prevList.ListOrder + 1
+		} else {
+			return errors.New("prev_list or next_list is required")
+		}
+
+		if err := tx.Model(&currList).Update("list_order", currList.ListOrder).Error; err != nil {
+			return fmt.Errorf("failed to update list: %w", err)
+		}
+		return nil
 	})
-	lists = append(lists, models.List{
-		Name:   "Rejected",
-		Color:  "#EB695B",
-		UserID: user.ID,
+}
+
+func (s *service) listRules(list models.List) (*ListRulesRes, error) {
+	var requirements []models.ListRequirement
+	if err := s.DB.Where("list_id = ?", list.ID).Order("id ASC").Find(&requirements).Error; err != nil {
+		return nil, err
+	}
+
+	var fieldIDs []uint
+	for _, r := range requirements {
+		if r.FieldID != 0 {
+			fieldIDs = append(fieldIDs, r.FieldID)
+		}
+	}
+	names := map[uint]string{}
+	if len(fieldIDs) > 0 {
+		var defs []models.FieldDefinition
+		if err := s.DB.Where("id IN ?", fieldIDs).Find(&defs).Error; err != nil {
+			return nil, err
+		}
+		for _, d := range defs {
+			names[d.ID] = d.Name
+		}
+	}
+
+	res := &ListRulesRes{ListID: list.ID, WIPLimit: list.WIPLimit, RequiredFields: []RequiredField{}}
+	for _, r := range requirements {
+		if r.FieldID != 0 {
+			if name, ok := names[r.FieldID]; ok {
+				res.RequiredFields = append(res.RequiredFields, RequiredField{FieldID: r.FieldID, Name: name})
+			}
+			continue
+		}
+		res.RequiredFields = append(res.RequiredFields, RequiredField{Field: r.Field, Name: r.Field})
+	}
+	return res, nil
+}
+
+func (s *service) GetListRules(c context.Context, req ListIDReq, user models.User) (*ListRulesRes, error) {
+	list, err := s.findList(req.ID, user)
+	if err != nil {
+		return nil, err
+	}
+	return s.listRules(*list)
+}
+
+// UpdateListRules replaces the WIP limit and required fields of a list. Cards
+// already in the list are not checked; the rules apply to cards entering it.
+func (s *service) UpdateListRules(c context.Context, req UpdateListRulesReq, user models.User) (*ListRulesRes, error) {
+	list, err := s.findList(req.ID, user)
+	if err != nil {
+		return nil, err
+	}
+	if req.WIPLimit < 0 {
+		return nil, errors.New("wip_limit cannot be negative")
+	}
+
+	var requirements []models.ListRequirement
+	seenFields := map[string]bool{}
+	for _, field := range req.RequiredFields {
+		if _, ok := models.CardFields[field]; !ok {
+			return nil, fmt.Errorf("unknown card field %s", field)
+		}
+		if !seenFields[field] {
+			seenFields[field] = true
+			requirements = append(requirements, models.ListRequirement{ListID: list.ID, Field: field})
+		}
+	}
+	seenIDs := map[uint]bool{}
+	for _, id := range req.RequiredFieldIDs {
+		if seenIDs[id] {
+			continue
+		}
+		seenIDs[id] = true
+		var fieldDef models.FieldDefinition
+		s.DB.Where("id = ? AND user_id = ?", id, user.ID).First(&fieldDef)
+		if fieldDef.ID == 0 {
+			return nil, fmt.Errorf("field definition %d does not exist", id)
+		}
+		if fieldDef.Archived {
+			return nil, fmt.Errorf("field %q is archived", fieldDef.Name)
+		}
+		requirements = append(requirements, models.ListRequirement{ListID: list.ID, FieldID: fieldDef.ID})
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(list).Update("wip_limit", req.WIPLimit).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("list_id = ?", list.ID).Delete(&models.ListRequirement{}).Error; err != nil {
+			return err
+		}
+		if len(requirements) == 0 {
+			return nil
+		}
+		return tx.Create(&requirements).Error
 	})
+	if err != nil {
+		logger.Logger.Error("failed to update list rules", zap.Error(err))
+		return nil, fmt.Errorf("failed to update list rules: %w", err)
+	}
+
+	return s.listRules(*list)
+}
 
-	s.DB.Create(&lists)
+// DeleteList soft-deletes a list. Its cards are either moved to the end of the
+// target list or trashed with the same deleted_at as the list, which is how
+// RestoreList finds them again.
+func (s *service) DeleteList(c context.Context, req DeleteListReq, user models.User) (*DeleteListRes, error) {
+	if req.TargetListID == 0 && !req.Cascade {
+		return nil, errors.New("target_list_id or cascade=true is required")
+	}
+	if req.TargetListID != 0 && req.Cascade {
+		return nil, errors.New("pass either target_list_id or cascade, not both")
+	}
+	if req.TargetListID == req.ID {
+		return nil, errors.New("target list must differ from the deleted list")
+	}
 
-	for _, list := range lists {
-		resLists = append(resLists, GetListResponse{
-			ID:        list.ID,
-			Name:      list.Name,
-			Color:     list.Color,
-			ListOrder: list.ListOrder,
-			CreatedAt: list.CreatedAt,
-			UpdatedAt: list.UpdatedAt,
+	list, err := s.findList(req.ID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &DeleteListRes{ID: list.ID}
+	var events []automation.Event
+
+	if req.Cascade {
+		err = s.DB.Transaction(func(tx *gorm.DB) error {
+			now := time.Now()
+			result := tx.Model(&models.Card{}).Where("list_id = ?", list.ID).Update("deleted_at", now)
+			if result.Error != nil {
+				return result.Error
+			}
+			res.TrashedCards = int(result.RowsAffected)
+			return tx.Model(list).Update("deleted_at", now).Error
+		})
+	} else {
+		var target *models.List
+		target, err = s.findList(req.TargetListID, user)
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.DB.Transaction(func(tx *gorm.DB) error {
+			if err := stagerules.Lock(tx, target); err != nil {
+				return err
+			}
+			var cards []models.Card
+			if err := tx.Where("list_id = ?", list.ID).Order("card_order ASC").Find(&cards).Error; err != nil {
+				return err
+			}
+
+			var maxOrder float64
+			tx.Model(&models.Card{}).Where("list_id = ?", target.ID).Select("COALESCE(MAX(card_order), 0)").Scan(&maxOrder)
+
+			var changes []models.CardHistory
+			var transitions []models.StageTransition
+			for _, _card := range cards {
+				// Cards moved earlier count against the target's WIP limit,
+				// so the first card that does not fit stops the delete.
+				if err := stagerules.Check(tx, *target, _card, nil); err != nil {
+					return fmt.Errorf("card %q: %w", _card.Name, err)
+				}
+				maxOrder++
+				if err := tx.Model(&_card).Updates(map[string]interface{}{"list_id": target.ID, "card_order": maxOrder}).Error; err != nil {
+					return err
+				}
+				changes = append(changes, history.Change(_card.ID, user.ID, models.CardHistoryList, "list", list.Name, target.Name))
+				transitions = append(transitions, analytics.Transition(_card.ID, list.ID, target.ID, user.ID))
+				events = append(events, automation.Event{Trigger: models.TriggerCardMoved, CardID: _card.ID, UserID: user.ID, ListID: target.ID})
+			}
+			res.MovedCards = len(cards)
+
+			// Cards already in the trash follow the list too, so they can
+			// still be restored.
+			if err := tx.Unscoped().Model(&models.Card{}).
+				Where("list_id = ? AND deleted_at IS NOT NULL", list.ID).
+				Update("list_id", target.ID).Error; err != nil {
+				return err
+			}
+			if err := history.Record(tx, changes...); err != nil {
+				return err
+			}
+			if err := analytics.RecordTransitions(tx, transitions...); err != nil {
+				return err
+			}
+			return tx.Delete(list).Error
 		})
 	}
-	logger.Logger.Info("Created default lists")
-	return &CreateDefaultListsRes{Lists: resLists}, nil
+	var ruleErr *stagerules.Error
+	if errors.As(err, &ruleErr) {
+		return nil, err
+	}
+	if err != nil {
+		logger.Logger.Error("failed to delete list", zap.Error(err))
+		return nil, fmt.Errorf("failed to delete list: %w", err)
+	}
+	automation.Dispatch(events...)
+
+	return res, nil
 }
 
-func (s *service) GetLists(c context.Context, user models.User) (*GetListsRes, error) {
+func (s *service) GetLists(c context.Context, req GetListsReq, user models.User) (*GetListsRes, error) {
 	var lists []models.List
 	var resLists []CardListResponse
 
-	s.DB.
+	_board, err := board.FindBoard(s.DB, req.BoardID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args, err := s.tagFilter(req, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.
+		Preload("Cards", func(db *gorm.DB) *gorm.DB {
+			if where == "" {
+				return db
+			}
+			return db.Where(where, args...)
+		}).
 		Preload("Cards.Tags").
-		Where("user_id = ?", user.ID).
-		Find(&lists)
+		Where("board_id = ?", _board.ID).
+		Order("list_order ASC, id ASC").
+		Find(&lists).Error; err != nil {
+		return nil, err
+	}
 
 	for _, list := range lists {
 		var cards []card.GetCard
 
 		for _, _card := range list.Cards {
-			var tags []tag.RespTag
-			for _, _tag := range _card.Tags {
-				tags = append(tags, tag.RespTag{
-					ID:    _tag.ID,
-					Name:  _tag.Name,
-					Color: _tag.Color,
-				})
-			}
-			cards = append(cards, card.GetCard{
-				ID:          _card.ID,
-				Name:        _card.Name,
-				Designation: _card.Designation,
-				Email:       _card.Email,
-				Phone:       _card.Phone,
-				ImageURL:    _card.ImageURL,
-				ListID:      _card.ListID,
-				CardOrder:   _card.CardOrder,
-				Tags:        tags,
-			})
+			cards = append(cards, toGetCard(_card))
 		}
 
 		sort.Slice(cards, func(i, j int) bool {
@@ -121,9 +493,309 @@ func (s *service) GetLists(c context.Context, user models.User) (*GetListsRes, e
 		})
 	}
 
-	sort.Slice(resLists, func(i, j int) bool {
+	sort.SliceStable(resLists, func(i, j int) bool {
 		return resLists[i].ListOrder < resLists[j].ListOrder
 	})
 
-	return &GetListsRes{Lists: resLists}, nil
+	return &GetListsRes{BoardID: _board.ID, Lists: resLists}, nil
+}
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// cardSort describes a server-side sort for paginated card listings. Every
+// sort is tie-broken on id so the cursor stays stable.
+type cardSort struct {
+	column string
+	desc   bool
+	key    func(c models.Card) string
+	parse  func(v string) (interface{}, error)
+}
+
+func parseTime(v string) (interface{}, error) {
+	return time.Parse(time.RFC3339Nano, v)
+}
+
+var cardSorts = map[string]cardSort{
+	"order": {
+		column: "card_order",
+		key:    func(c models.Card) string { return strconv.FormatFloat(c.CardOrder, 'f', -1, 64) },
+		parse:  func(v string) (interface{}, error) { return strconv.ParseFloat(v, 64) },
+	},
+	"name": {
+		column: "name",
+		key:    func(c models.Card) string { return c.Name },
+		parse:  func(v string) (interface{}, error) { return v, nil },
+	},
+	"created_at": {
+		column: "created_at",
+		desc:   true,
+		key:    func(c models.Card) string { return c.CreatedAt.Format(time.RFC3339Nano) },
+		parse:  parseTime,
+	},
+	"updated_at": {
+		column: "updated_at",
+		desc:   true,
+		key:    func(c models.Card) string { return c.UpdatedAt.Format(time.RFC3339Nano) },
+		parse:  parseTime,
+	},
+}
+
+type cardCursor struct {
+	Value string `json:"v"`
+	ID    uint   `json:"id"`
+}
+
+func encodeCursor(cur cardCursor) string {
+	b, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (cardCursor, error) {
+	var cur cardCursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cur, err
+	}
+	err = json.Unmarshal(b, &cur)
+	return cur, err
+}
+
+func toGetCard(_card models.Card) card.GetCard {
+	var tags []tag.RespTag
+	for _, _tag := range _card.Tags {
+		tags = append(tags, tag.RespTag{
+			ID:    _tag.ID,
+			Name:  _tag.Name,
+			Color: _tag.Color,
+		})
+	}
+	return card.GetCard{
+		ID:          _card.ID,
+		Name:        _card.Name,
+		Designation: _card.Designation,
+		Email:       _card.Email,
+		Phone:       _card.Phone,
+		ImageURL:    _card.ImageURL,
+		ListID:      _card.ListID,
+		CardOrder:   _card.CardOrder,
+		Tags:        tags,
+	}
+}
+
+func (s *service) GetListCards(c context.Context, req GetListCardsReq, user models.User) (*GetListCardsRes, error) {
+	var list models.List
+	s.DB.Where("id = ? AND user_id = ?", req.ID, user.ID).First(&list)
+	if list.ID == 0 {
+		logger.Logger.Error("list not found", zap.String("list_id", strconv.Itoa(int(req.ID))))
+		return nil, errors.New("list not found")
+	}
+
+	sortName := req.Sort
+	if sortName == "" {
+		sortName = "order"
+	}
+	sortBy, ok := cardSorts[sortName]
+	if !ok {
+		return nil, fmt.Errorf("invalid sort: %s", req.Sort)
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	direction, cmp := "ASC", ">"
+	if sortBy.desc {
+		direction, cmp = "DESC", "<"
+	}
+
+	query := s.DB.Preload("Tags").Where("list_id = ?", list.ID)
+	if req.Cursor != "" {
+		cur, err := decodeCursor(req.Cursor)
+		if err != nil {
+			return nil, errors.New("invalid cursor")
+		}
+		value, err := sortBy.parse(cur.Value)
+		if err != nil {
+			return nil, errors.New("invalid cursor")
+		}
+		query = query.Where(fmt.Sprintf("(%s, id) %s (?, ?)", sortBy.column, cmp), value, cur.ID)
+	}
+
+	var cards []models.Card
+	if err := query.
+		Order(fmt.Sprintf("%s %s, id %s", sortBy.column, direction, direction)).
+		Limit(limit + 1).
+		Find(&cards).Error; err != nil {
+		return nil, err
+	}
+
+	res := &GetListCardsRes{
+		ListID: list.ID,
+		Sort:   sortName,
+		Cards:  []card.GetCard{},
+	}
+	if len(cards) > limit {
+		cards = cards[:limit]
+		res.HasMore = true
+		last := cards[len(cards)-1]
+		res.NextCursor = encodeCursor(cardCursor{Value: sortBy.key(last), ID: last.ID})
+	}
+	for _, _card := range cards {
+		res.Cards = append(res.Cards, toGetCard(_card))
+	}
+
+	return res, nil
+}
+
+// tagFilter compiles the tag filters of req to a condition on cards.
+func (s *service) tagFilter(req GetListsReq, user models.User) (string, []interface{}, error) {
+	var filter models.CardFilter
+	for _, f := range []struct {
+		ids      []uint
+		operator string
+	}{
+		{req.TagsAny, "has_any"},
+		{req.TagsAll, "has_all"},
+		{req.TagsNone, "has_none"},
+	} {
+		if len(f.ids) > 0 {
+			filter.Conditions = append(filter.Conditions, models.CardCondition{TagIDs: f.ids, Operator: f.operator})
+		}
+	}
+	if len(filter.Conditions) == 0 {
+		return "", nil, nil
+	}
+	if err := cardquery.Validate(s.DB, filter, models.CardSort{}, user.ID); err != nil {
+		return "", nil, err
+	}
+	return cardquery.Clause(filter, nil)
+}
+
+func (s *service) GetBoardSummary(c context.Context, req GetListsReq, user models.User) (*BoardSummaryRes, error) {
+	var lists []ListSummary
+
+	_board, err := board.FindBoard(s.DB, req.BoardID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args, err := s.tagFilter(req, user)
+	if err != nil {
+		return nil, err
+	}
+	join := "LEFT JOIN cards ON cards.list_id = lists.id AND cards.deleted_at IS NULL"
+	if where != "" {
+		join += " AND " + where
+	}
+
+	err = s.DB.Model(&models.List{}).
+		Select("lists.id, lists.name, lists.color, lists.list_order, lists.wip_limit, lists.created_at, lists.updated_at, COUNT(cards.id) AS card_count").
+		Joins(join, args...).
+		Where("lists.board_id = ?", _board.ID).
+		Group("lists.id").
+		Order("lists.list_order ASC, lists.id ASC").
+		Scan(&lists).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &BoardSummaryRes{BoardID: _board.ID, Lists: lists}, nil
+}
+
+// PurgeLists hard-deletes lists and every card in them, trashed or not.
+func PurgeLists(db *gorm.DB, listIDs []uint) error {
+	if len(listIDs) == 0 {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		var cardIDs []uint
+		if err := tx.Unscoped().Model(&models.Card{}).Where("list_id IN ?", listIDs).Pluck("id", &cardIDs).Error; err != nil {
+			return err
+		}
+		if err := card.PurgeCards(tx, cardIDs); err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("list_id IN ?", listIDs).Delete(&models.ListRequirement{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id IN ?", listIDs).Delete(&models.List{}).Error
+	})
+}
+
+func (s *service) findTrashedList(id uint, user models.User) (*models.List, error) {
+	var list models.List
+	s.DB.Unscoped().Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, user.ID).First(&list)
+	if list.ID == 0 {
+		logger.Logger.Error("list not in trash", zap.String("list_id", strconv.Itoa(int(id))))
+		return nil, errors.New("list not in trash")
+	}
+	return &list, nil
+}
+
+func (s *service) GetTrash(c context.Context, user models.User) (*GetTrashRes, error) {
+	lists := []TrashList{}
+
+	err := s.DB.Unscoped().Model(&models.List{}).
+		Select("lists.id, lists.name, lists.color, lists.deleted_at, COUNT(cards.id) AS card_count").
+		Joins("LEFT JOIN cards ON cards.list_id = lists.id").
+		Where("lists.user_id = ? AND lists.deleted_at IS NOT NULL", user.ID).
+		Group("lists.id").
+		Order("lists.deleted_at DESC").
+		Scan(&lists).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetTrashRes{Lists: lists}, nil
+}
+
+// RestoreList restores a list along with the cards that were trashed with it.
+// The cards enter the list again one by one, so the first card that does not
+// meet the list's entry rules stops the restore.
+func (s *service) RestoreList(c context.Context, req ListIDReq, user models.User) error {
+	list, err := s.findTrashedList(req.ID, user)
+	if err != nil {
+		return err
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		var cards []models.Card
+		if err := tx.Unscoped().
+			Where("list_id = ? AND deleted_at = ?", list.ID, list.DeletedAt.Time).
+			Order("card_order ASC").
+			Find(&cards).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(list).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		if err := stagerules.Lock(tx, list); err != nil {
+			return err
+		}
+		for _, _card := range cards {
+			if err := stagerules.Check(tx, *list, _card, nil); err != nil {
+				return fmt.Errorf("card %q: %w", _card.Name, err)
+			}
+			if err := tx.Unscoped().Model(&_card).Update("deleted_at", nil).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *service) DeleteListPermanently(c context.Context, req ListIDReq, user models.User) error {
+	list, err := s.findTrashedList(req.ID, user)
+	if err != nil {
+		return err
+	}
+
+	return PurgeLists(s.DB, []uint{list.ID})
 }
@@ -0,0 +1,48 @@
+package list
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Cognize-AI/client-cognize/models"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	created := time.Date(2025, 3, 4, 5, 6, 7, 891011000, time.UTC)
+	card := models.Card{Name: "Zoë, \"The\" Boss", CardOrder: 1.25}
+	card.ID = 42
+	card.CreatedAt = created
+	card.UpdatedAt = created.Add(time.Hour)
+
+	for name, sortBy := range cardSorts {
+		encoded := encodeCursor(cardCursor{Value: sortBy.key(card), ID: card.ID})
+		cur, err := decodeCursor(encoded)
+		if err != nil {
+			t.Fatalf("%s: decodeCursor(%q): %v", name, encoded, err)
+		}
+		if cur.ID != card.ID {
+			t.Errorf("%s: cursor id = %d, want %d", name, cur.ID, card.ID)
+		}
+		if _, err := sortBy.parse(cur.Value); err != nil {
+			t.Errorf("%s: parse(%q): %v", name, cur.Value, err)
+		}
+	}
+
+	cur, _ := decodeCursor(encodeCursor(cardCursor{Value: cardSorts["created_at"].key(card), ID: 1}))
+	value, err := cardSorts["created_at"].parse(cur.Value)
+	if err != nil || !value.(time.Time).Equal(created) {
+		t.Errorf("created_at cursor = %v (%v), want %v with nanoseconds", value, err, created)
+	}
+	cur, _ = decodeCursor(encodeCursor(cardCursor{Value: cardSorts["order"].key(card), ID: 1}))
+	if value, err := cardSorts["order"].parse(cur.Value); err != nil || value.(float64) != 1.25 {
+		t.Errorf("order cursor = %v (%v), want 1.25", value, err)
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	for _, s := range []string{"!!!", "bm90IGpzb24", "eyJ2IjoxfQ"} {
+		if _, err := decodeCursor(s); err == nil {
+			t.Errorf("decodeCursor(%q) succeeded, want an error", s)
+		}
+	}
+}
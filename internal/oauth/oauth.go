@@ -2,12 +2,18 @@ package oauth
 
 import "context"
 
+type GetRedirectURLReq struct {
+	// Template is the built-in pipeline template a new account starts with.
+	Template string `form:"template"`
+}
+
 type GetRedirectURLResp struct {
 	RedirectURL string `json:"redirect_url"`
 }
 
 type HandleGoogleCallbackReq struct {
-	Code string `json:"code"`
+	Code  string `json:"code"`
+	State string `json:"state"`
 }
 type HandleGoogleCallbackResp struct {
 	Token          string `json:"token"`
@@ -28,6 +34,6 @@ type GoogleUser struct {
 }
 
 type Service interface {
-	GetRedirectURL(c context.Context) (*GetRedirectURLResp, error)
+	GetRedirectURL(c context.Context, req GetRedirectURLReq) (*GetRedirectURLResp, error)
 	HandleGoogleCallback(c context.Context, req *HandleGoogleCallbackReq) (*HandleGoogleCallbackResp, error)
 }
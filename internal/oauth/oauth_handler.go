@@ -1,10 +1,8 @@
 package oauth
 
 import (
-	"log"
 	"net/http"
 
-	"github.com/Cognize-AI/client-cognize/config"
 	"github.com/gin-gonic/gin"
 )
 
@@ -17,7 +15,13 @@ func NewHandler(s Service) *Handler {
 }
 
 func (h *Handler) GetRedirectURL(c *gin.Context) {
-	res, err := h.Service.GetRedirectURL(c)
+	var req GetRedirectURLReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.Service.GetRedirectURL(c, req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -32,17 +36,12 @@ func (h *Handler) HandleGoogleCallback(c *gin.Context) {
 		return
 	}
 
-	res, err := h.Service.HandleGoogleCallback(c, &HandleGoogleCallbackReq{Code: code})
+	res, err := h.Service.HandleGoogleCallback(c, &HandleGoogleCallbackReq{Code: code, State: c.Query("state")})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	_config, err := config.LoadConfig(".")
-	if err != nil {
-		log.Fatal(err)
-	}
-
 	if _config.Environment != "dev" {
 		c.SetSameSite(http.SameSiteNoneMode)
 		c.SetCookie("Authorization", res.Token, 3600*24*30, "/", "", true, true)
@@ -2,13 +2,23 @@ package oauth
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Cognize-AI/client-cognize/config"
+	"github.com/Cognize-AI/client-cognize/internal/analytics"
+	"github.com/Cognize-AI/client-cognize/internal/board"
+	"github.com/Cognize-AI/client-cognize/internal/pipeline"
 	"github.com/Cognize-AI/client-cognize/logger"
 	"github.com/Cognize-AI/client-cognize/models"
 	"github.com/Cognize-AI/client-cognize/util"
@@ -18,6 +28,17 @@ import (
 	"gorm.io/gorm"
 )
 
+// _config is read once at startup; the JWT secret also signs the oauth state.
+var _config config.Config
+
+func init() {
+	var err error
+	_config, err = config.LoadConfig(".")
+	if err != nil {
+		panic(err)
+	}
+}
+
 type service struct {
 	timeout time.Duration
 	DB      *gorm.DB
@@ -30,8 +51,63 @@ func NewService() Service {
 	}
 }
 
-func (s *service) GetRedirectURL(c context.Context) (*GetRedirectURLResp, error) {
-	url := config.GoogleOAuthConfig.AuthCodeURL("random-state-token", oauth2.AccessTypeOffline)
+// stateTTL is how long a redirect URL can be used to sign in.
+const stateTTL = 30 * time.Minute
+
+func stateSignature(payload, secret string) string {
+	mac := hmac.New(sha256.New, []byte("oauth-state:"+secret))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// newState builds the state sent to Google and returned on the callback: a
+// random nonce, an expiry and the pipeline template chosen for a new account,
+// signed so the callback can trust the template.
+func newState(template, secret string) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%s|%d|%s",
+		base64.RawURLEncoding.EncodeToString(nonce), time.Now().Add(stateTTL).Unix(), template)))
+	return payload + "." + stateSignature(payload, secret), nil
+}
+
+// parseState checks the state of a callback and returns its template.
+func parseState(state, secret string) (string, error) {
+	invalid := errors.New("invalid or expired oauth state")
+	payload, signature, ok := strings.Cut(state, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(stateSignature(payload, secret))) {
+		return "", invalid
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", invalid
+	}
+	parts := strings.SplitN(string(raw), "|", 3)
+	if len(parts) != 3 {
+		return "", invalid
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return "", invalid
+	}
+	return parts[2], nil
+}
+
+func (s *service) GetRedirectURL(c context.Context, req GetRedirectURLReq) (*GetRedirectURLResp, error) {
+	if req.Template != "" {
+		if _, ok := pipeline.Builtin(req.Template); !ok {
+			return nil, errors.New("unknown template: " + req.Template)
+		}
+	}
+
+	state, err := newState(req.Template, _config.JwtSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	url := config.GoogleOAuthConfig.AuthCodeURL(state, oauth2.AccessTypeOffline)
 	return &GetRedirectURLResp{
 		RedirectURL: url,
 	}, nil
@@ -68,9 +144,9 @@ func createAPIKey(s *service, user models.User) {
 }
 
 func (s *service) HandleGoogleCallback(c context.Context, req *HandleGoogleCallbackReq) (*HandleGoogleCallbackResp, error) {
-	_config, err := config.LoadConfig(".")
+	templateKey, err := parseState(req.State, _config.JwtSecret)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	token, err := config.GoogleOAuthConfig.Exchange(context.Background(), req.Code)
@@ -100,86 +176,95 @@ func (s *service) HandleGoogleCallback(c context.Context, req *HandleGoogleCallb
 			Password:       "",
 			ProfilePicture: googleUser.Picture,
 		}
-		s.DB.Create(&user)
+		err := s.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&user).Error; err != nil {
+				return err
+			}
 
-		createAPIKey(s, user)
+			_board, err := board.DefaultBoard(tx, user.ID)
+			if err != nil {
+				logger.Logger.Error("failed to create default board", zap.Error(err))
+				return err
+			}
 
-		var lists []models.List
+			template, err := pipeline.Resolve(tx, templateKey, 0, user.ID)
+			if err != nil {
+				template, _ = pipeline.Resolve(tx, "", 0, user.ID)
+			}
+			lists, err := pipeline.Apply(tx, *template, user.ID, _board.ID)
+			if err != nil {
+				logger.Logger.Error("failed to create lists from template", zap.Error(err))
+				return err
+			}
 
-		lists = append(lists, models.List{
-			Name:   "New Leads",
-			Color:  "#F9BA0B",
-			UserID: user.ID,
-		})
-		lists = append(lists, models.List{
-			Name:   "Follow Up",
-			Color:  "#40C2FC",
-			UserID: user.ID,
-		})
-		lists = append(lists, models.List{
-			Name:   "Qualified",
-			Color:  "#75C699",
-			UserID: user.ID,
-		})
-		lists = append(lists, models.List{
-			Name:   "Rejected",
-			Color:  "#EB695B",
-			UserID: user.ID,
-		})
+			var maxOrder float64
+			tx.Model(&models.Card{}).Select("COALESCE(MAX(card_order), 0)").Scan(&maxOrder)
 
-		s.DB.Create(&lists)
-
-		var maxOrder float64
-		s.DB.Model(&models.Card{}).Select("COALESCE(MAX(card_order), 0)").Scan(&maxOrder)
-
-		var cards []models.Card
-		cards = append(cards, models.Card{
-			Name:        "Prashant Kumar Singh",
-			Designation: "Product Designer",
-			Email:       "prashantkumarsingh.work@gmail.com",
-			ImageURL:    "https://res.cloudinary.com/doigbqz0a/image/upload/v1756590064/my-project-folder/yalgse87vdd6jltfuwcl.png",
-			ListID:      lists[0].ID,
-			CardOrder:   maxOrder + 1,
-		}, models.Card{
-			Name:        "Anurag Daksh",
-			Designation: "Full Stack Developer",
-			Email:       "anuragdaksh.work@gmail.com",
-			ImageURL:    "https://lh3.googleusercontent.com/a/ACg8ocJw2xWE84QKYmFuzKTPglJM75nl3SFjohtEvDSkVy1thdiTDaeS6g=s96-c",
-			ListID:      lists[0].ID,
-			CardOrder:   maxOrder + 2,
-		}, models.Card{
-			Name:        "Rohit Chand",
-			Designation: "Frontend Developer",
-			Email:       "rohitchand010904@gmail.com",
-			ImageURL:    "https://lh3.googleusercontent.com/a/ACg8ocJqcXOqco1cLcCG3WJ3Z12GlNoXGRCRidYWuxTE_VVamqY2se4w=s96-c",
-			ListID:      lists[0].ID,
-			CardOrder:   maxOrder + 3,
-		})
-		s.DB.Create(&cards)
-
-		var tags []models.Tag
-		tags = append(tags, models.Tag{
-			Name:   "ux researcher",
-			Color:  "#A78BFA",
-			UserID: user.ID,
-		}, models.Tag{
-			Name:   "product designer",
-			Color:  "#FCA5A5",
-			UserID: user.ID,
-		}, models.Tag{
-			Name:   "content strategist",
-			Color:  "#34D399",
-			UserID: user.ID,
-		}, models.Tag{
-			Name:   "SEO specialist",
-			Color:  "#60A5FA",
-			UserID: user.ID,
-		}, models.Tag{
-			Name:   "brand strategist",
-			Color:  "#FBBF24",
-			UserID: user.ID,
+			var cards []models.Card
+			cards = append(cards, models.Card{
+				Name:        "Prashant Kumar Singh",
+				Designation: "Product Designer",
+				Email:       "prashantkumarsingh.work@gmail.com",
+				ImageURL:    "https://res.cloudinary.com/doigbqz0a/image/upload/v1756590064/my-project-folder/yalgse87vdd6jltfuwcl.png",
+				ListID:      lists[0].ID,
+				CardOrder:   maxOrder + 1,
+			}, models.Card{
+				Name:        "Anurag Daksh",
+				Designation: "Full Stack Developer",
+				Email:       "anuragdaksh.work@gmail.com",
+				ImageURL:    "https://lh3.googleusercontent.com/a/ACg8ocJw2xWE84QKYmFuzKTPglJM75nl3SFjohtEvDSkVy1thdiTDaeS6g=s96-c",
+				ListID:      lists[0].ID,
+				CardOrder:   maxOrder + 2,
+			}, models.Card{
+				Name:        "Rohit Chand",
+				Designation: "Frontend Developer",
+				Email:       "rohitchand010904@gmail.com",
+				ImageURL:    "https://lh3.googleusercontent.com/a/ACg8ocJqcXOqco1cLcCG3WJ3Z12GlNoXGRCRidYWuxTE_VVamqY2se4w=s96-c",
+				ListID:      lists[0].ID,
+				CardOrder:   maxOrder + 3,
+			})
+			if err := tx.Create(&cards).Error; err != nil {
+				return err
+			}
+
+			var transitions []models.StageTransition
+			for _, _card := range cards {
+				transitions = append(transitions, analytics.Transition(_card.ID, 0, _card.ListID, user.ID))
+			}
+			if err := analytics.RecordTransitions(tx, transitions...); err != nil {
+				return err
+			}
+
+			var tags []models.Tag
+			tags = append(tags, models.Tag{
+				Name:   "ux researcher",
+				Color:  "#A78BFA",
+				UserID: user.ID,
+			}, models.Tag{
+				Name:   "product designer",
+				Color:  "#FCA5A5",
+				UserID: user.ID,
+			}, models.Tag{
+				Name:   "content strategist",
+				Color:  "#34D399",
+				UserID: user.ID,
+			}, models.Tag{
+				Name:   "SEO specialist",
+				Color:  "#60A5FA",
+				UserID: user.ID,
+			}, models.Tag{
+				Name:   "brand strategist",
+				Color:  "#FBBF24",
+				UserID: user.ID,
+			})
+			return tx.Create(&tags).Error
 		})
-		s.DB.Create(&tags)
+		if err != nil {
+			logger.Logger.Error("failed to set up new account", zap.Error(err))
+			return nil, err
+		}
+
+		createAPIKey(s, user)
 	} else {
 		user.ProfilePicture = googleUser.Picture
 		s.DB.Save(&user)
@@ -0,0 +1,66 @@
+package oauth
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseState(t *testing.T) {
+	const secret = "test-secret"
+	valid, err := newState("sales", secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	empty, err := newState("", secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// signed builds a state with a valid signature around any payload.
+	signed := func(raw string) string {
+		payload := base64.RawURLEncoding.EncodeToString([]byte(raw))
+		return payload + "." + stateSignature(payload, secret)
+	}
+	payload, signature, _ := strings.Cut(valid, ".")
+	forged := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("nonce|%d|recruiting", time.Now().Add(time.Hour).Unix())))
+
+	tests := []struct {
+		name     string
+		state    string
+		template string
+		wantErr  bool
+	}{
+		{"valid", valid, "sales", false},
+		{"valid without template", empty, "", false},
+		{"wrong secret", func() string { s, _ := newState("sales", "other"); return s }(), "", true},
+		{"tampered payload", forged + "." + signature, "", true},
+		{"tampered signature", payload + "." + strings.Repeat("A", len(signature)), "", true},
+		{"missing signature", payload, "", true},
+		{"empty", "", "", true},
+		{"expired", signed(fmt.Sprintf("nonce|%d|sales", time.Now().Add(-time.Minute).Unix())), "", true},
+		{"bad expiry", signed("nonce|soon|sales"), "", true},
+		{"too few parts", signed("nonce|sales"), "", true},
+		{"payload not base64", "!!!." + stateSignature("!!!", secret), "", true},
+	}
+	for _, tt := range tests {
+		template, err := parseState(tt.state, secret)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: parseState error = %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if template != tt.template {
+			t.Errorf("%s: template = %q, want %q", tt.name, template, tt.template)
+		}
+	}
+}
+
+func TestNewStateIsUnique(t *testing.T) {
+	a, _ := newState("sales", "secret")
+	b, _ := newState("sales", "secret")
+	if a == b {
+		t.Errorf("newState returned the same state twice: %q", a)
+	}
+}
@@ -0,0 +1,112 @@
+package pipeline
+
+import "github.com/Cognize-AI/client-cognize/models"
+
+// DefaultTemplate is used when no template is chosen.
+const DefaultTemplate = "sales"
+
+var builtinOrder = []string{"sales", "recruiting", "fundraising", "partnerships"}
+
+var builtins = map[string]Template{
+	"sales": {
+		Name:        "Sales",
+		Description: "Qualify inbound and outbound leads.",
+		Lists: []models.TemplateList{
+			{Name: "New Leads", Color: "#F9BA0B"},
+			{Name: "Follow Up", Color: "#40C2FC"},
+			{Name: "Qualified", Color: "#75C699"},
+			{Name: "Rejected", Color: "#EB695B"},
+		},
+		Tags: []models.TemplateTag{
+			{Name: "hot lead", Color: "#FCA5A5"},
+			{Name: "decision maker", Color: "#A78BFA"},
+			{Name: "referral", Color: "#34D399"},
+		},
+		Fields: []models.TemplateField{
+			{Name: "Lead Source", Type: string(models.CardTypeContact), DataType: string(models.DataTypeSelect), Options: []models.TemplateOption{
+				{Name: "Inbound", Color: "#75C699"},
+				{Name: "Outbound", Color: "#40C2FC"},
+				{Name: "Referral", Color: "#A78BFA"},
+				{Name: "Event", Color: "#FBBF24"},
+			}},
+			{Name: "Industry", Type: string(models.CardTypeCompany)},
+			{Name: "Deal Size", Type: string(models.CardTypeCompany), DataType: string(models.DataTypeCurrency)},
+		},
+	},
+	"recruiting": {
+		Name:        "Recruiting",
+		Description: "Move candidates from sourcing to hire.",
+		Lists: []models.TemplateList{
+			{Name: "Sourced", Color: "#F9BA0B"},
+			{Name: "Screening", Color: "#40C2FC"},
+			{Name: "Interviewing", Color: "#A78BFA"},
+			{Name: "Offer", Color: "#FBBF24"},
+			{Name: "Hired", Color: "#75C699"},
+			{Name: "Rejected", Color: "#EB695B"},
+		},
+		Tags: []models.TemplateTag{
+			{Name: "engineering", Color: "#60A5FA"},
+			{Name: "design", Color: "#A78BFA"},
+			{Name: "referral", Color: "#34D399"},
+		},
+		Fields: []models.TemplateField{
+			{Name: "Role", Type: string(models.CardTypeContact)},
+			{Name: "Resume", Type: string(models.CardTypeContact), DataType: string(models.DataTypeURL)},
+			{Name: "Expected Salary", Type: string(models.CardTypeContact), DataType: string(models.DataTypeCurrency)},
+		},
+	},
+	"fundraising": {
+		Name:        "Fundraising",
+		Description: "Track investor conversations through a round.",
+		Lists: []models.TemplateList{
+			{Name: "Prospects", Color: "#F9BA0B"},
+			{Name: "Intro Requested", Color: "#40C2FC"},
+			{Name: "Pitched", Color: "#A78BFA"},
+			{Name: "Due Diligence", Color: "#FBBF24"},
+			{Name: "Committed", Color: "#75C699"},
+			{Name: "Passed", Color: "#EB695B"},
+		},
+		Tags: []models.TemplateTag{
+			{Name: "angel", Color: "#FCA5A5"},
+			{Name: "vc", Color: "#60A5FA"},
+			{Name: "lead investor", Color: "#34D399"},
+		},
+		Fields: []models.TemplateField{
+			{Name: "Check Size", Type: string(models.CardTypeContact), DataType: string(models.DataTypeCurrency)},
+			{Name: "Fund", Type: string(models.CardTypeCompany)},
+			{Name: "Stage Focus", Type: string(models.CardTypeCompany)},
+		},
+	},
+	"partnerships": {
+		Name:        "Partnerships",
+		Description: "Find, negotiate and manage partners.",
+		Lists: []models.TemplateList{
+			{Name: "Identified", Color: "#F9BA0B"},
+			{Name: "Outreach", Color: "#40C2FC"},
+			{Name: "In Discussion", Color: "#A78BFA"},
+			{Name: "Agreement", Color: "#FBBF24"},
+			{Name: "Active", Color: "#75C699"},
+			{Name: "Declined", Color: "#EB695B"},
+		},
+		Tags: []models.TemplateTag{
+			{Name: "integration", Color: "#60A5FA"},
+			{Name: "reseller", Color: "#FBBF24"},
+			{Name: "co-marketing", Color: "#34D399"},
+		},
+		Fields: []models.TemplateField{
+			{Name: "Partner Type", Type: string(models.CardTypeCompany)},
+			{Name: "Contract Renewal", Type: string(models.CardTypeCompany), DataType: string(models.DataTypeDate)},
+		},
+	},
+}
+
+// Builtin returns the built-in template with the given key.
+func Builtin(key string) (Template, bool) {
+	t, ok := builtins[key]
+	if !ok {
+		return Template{}, false
+	}
+	t.Key = key
+	t.BuiltIn = true
+	return t, true
+}
@@ -0,0 +1,54 @@
+package pipeline
+
+import (
+	"context"
+	"time"
+
+	"github.com/Cognize-AI/client-cognize/models"
+)
+
+// Template is a built-in template, identified by Key, or a saved one,
+// identified by ID.
+type Template struct {
+	Key         string                 `json:"key,omitempty"`
+	ID          uint                   `json:"id,omitempty"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	BuiltIn     bool                   `json:"built_in"`
+	Lists       []models.TemplateList  `json:"lists"`
+	Tags        []models.TemplateTag   `json:"tags"`
+	Fields      []models.TemplateField `json:"fields"`
+	CreatedAt   *time.Time             `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time             `json:"updated_at,omitempty"`
+}
+
+type GetTemplatesRes struct {
+	Templates []Template `json:"templates"`
+}
+
+type TemplateReq struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	// BoardID copies the lists of a board, the tags used on its cards and the
+	// user's custom fields instead of taking Lists, Tags and Fields.
+	BoardID uint                   `json:"board_id"`
+	Lists   []models.TemplateList  `json:"lists"`
+	Tags    []models.TemplateTag   `json:"tags"`
+	Fields  []models.TemplateField `json:"fields"`
+}
+
+type UpdateTemplateReq struct {
+	ID uint `uri:"id" binding:"required"`
+	TemplateReq
+}
+
+type TemplateIDReq struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+type Service interface {
+	GetTemplates(ctx context.Context, user models.User) (*GetTemplatesRes, error)
+	CreateTemplate(ctx context.Context, req TemplateReq, user models.User) (*Template, error)
+	UpdateTemplate(ctx context.Context, req UpdateTemplateReq, user models.User) (*Template, error)
+	DeleteTemplate(ctx context.Context, req TemplateIDReq, user models.User) error
+}
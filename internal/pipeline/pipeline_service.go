@@ -0,0 +1,386 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Cognize-AI/client-cognize/config"
+	"github.com/Cognize-AI/client-cognize/internal/fieldtype"
+	"github.com/Cognize-AI/client-cognize/logger"
+	"github.com/Cognize-AI/client-cognize/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	maxTemplateLists  = 30
+	maxTemplateTags   = 100
+	maxTemplateFields = 100
+	defaultListColor  = "#40C2FC"
+	defaultTagColor   = "#60A5FA"
+)
+
+type service struct {
+	timeout time.Duration
+	DB      *gorm.DB
+}
+
+func NewService() Service {
+	return &service{
+		time.Duration(20) * time.Second,
+		config.DB,
+	}
+}
+
+func toTemplate(t models.PipelineTemplate) Template {
+	res := Template{
+		ID:          t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		Lists:       t.Lists,
+		Tags:        t.Tags,
+		Fields:      t.Fields,
+		CreatedAt:   &t.CreatedAt,
+		UpdatedAt:   &t.UpdatedAt,
+	}
+	if res.Tags == nil {
+		res.Tags = []models.TemplateTag{}
+	}
+	if res.Fields == nil {
+		res.Fields = []models.TemplateField{}
+	}
+	return res
+}
+
+// Resolve finds the template to build a board from: the user's saved
+// template id, else the built-in key, else DefaultTemplate.
+func Resolve(db *gorm.DB, key string, id, userID uint) (*Template, error) {
+	if id != 0 {
+		var saved models.PipelineTemplate
+		db.Where("id = ? AND user_id = ?", id, userID).First(&saved)
+		if saved.ID == 0 {
+			logger.Logger.Error("template not found", zap.String("template_id", strconv.Itoa(int(id))))
+			return nil, errors.New("template not found")
+		}
+		t := toTemplate(saved)
+		return &t, nil
+	}
+
+	if key == "" {
+		key = DefaultTemplate
+	}
+	t, ok := Builtin(key)
+	if !ok {
+		return nil, fmt.Errorf("unknown template %q", key)
+	}
+	return &t, nil
+}
+
+// Apply creates the template's lists on the board, in order, and the tags
+// and custom fields the user does not have yet. Pass the transaction that
+// creates the board.
+func Apply(db *gorm.DB, t Template, userID, boardID uint) ([]models.List, error) {
+	lists := make([]models.List, 0, len(t.Lists))
+	for i, l := range t.Lists {
+		lists = append(lists, models.List{
+			Name:      l.Name,
+			Color:     l.Color,
+			UserID:    userID,
+			BoardID:   boardID,
+			ListOrder: float64(i + 1),
+			WIPLimit:  l.WIPLimit,
+		})
+	}
+	if len(lists) > 0 {
+		if err := db.Create(&lists).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if len(t.Tags) > 0 {
+		var names []string
+		if err := db.Model(&models.Tag{}).Where("user_id = ?", userID).Pluck("LOWER(name)", &names).Error; err != nil {
+			return nil, err
+		}
+		existing := map[string]bool{}
+		for _, name := range names {
+			existing[name] = true
+		}
+		var tags []models.Tag
+		for _, tag := range t.Tags {
+			if existing[strings.ToLower(tag.Name)] {
+				continue
+			}
+			existing[strings.ToLower(tag.Name)] = true
+			tags = append(tags, models.Tag{Name: tag.Name, Color: tag.Color, UserID: userID})
+		}
+		if len(tags) > 0 {
+			if err := db.Create(&tags).Error; err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(t.Fields) > 0 {
+		var defs []models.FieldDefinition
+		if err := db.Where("user_id = ?", userID).Find(&defs).Error; err != nil {
+			return nil, err
+		}
+		existing := map[string]bool{}
+		// FieldDefinition.BeforeCreate would give every field of one batch
+		// the same order, so the new fields are numbered here.
+		maxOrder := map[string]float64{}
+		for _, def := range defs {
+			existing[def.Type+":"+strings.ToLower(def.Name)] = true
+			maxOrder[def.Type] = math.Max(maxOrder[def.Type], def.FieldOrder)
+		}
+		var fields []models.FieldDefinition
+		for _, field := range t.Fields {
+			key := field.Type + ":" + strings.ToLower(field.Name)
+			if existing[key] {
+				continue
+			}
+			existing[key] = true
+			def := fieldDefinition(field, userID)
+			if err := fieldtype.Prepare(&def); err != nil {
+				return nil, fmt.Errorf("field %q: %w", field.Name, err)
+			}
+			maxOrder[def.Type]++
+			def.FieldOrder = maxOrder[def.Type]
+			fields = append(fields, def)
+		}
+		if len(fields) > 0 {
+			if err := db.Create(&fields).Error; err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return lists, nil
+}
+
+func (s *service) GetTemplates(ctx context.Context, user models.User) (*GetTemplatesRes, error) {
+	res := &GetTemplatesRes{Templates: []Template{}}
+	for _, key := range builtinOrder {
+		t, _ := Builtin(key)
+		res.Templates = append(res.Templates, t)
+	}
+
+	var saved []models.PipelineTemplate
+	if err := s.DB.Where("user_id = ?", user.ID).Order("name ASC, id ASC").Find(&saved).Error; err != nil {
+		return nil, err
+	}
+	for _, t := range saved {
+		res.Templates = append(res.Templates, toTemplate(t))
+	}
+	return res, nil
+}
+
+// normalize trims the template's names, fills in default colors and checks
+// that it can be applied.
+func normalize(req *TemplateReq) error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(req.Lists) == 0 {
+		return errors.New("a template needs at least one list")
+	}
+	if len(req.Lists) > maxTemplateLists {
+		return fmt.Errorf("a template can have at most %d lists", maxTemplateLists)
+	}
+	if len(req.Tags) > maxTemplateTags {
+		return fmt.Errorf("a template can have at most %d tags", maxTemplateTags)
+	}
+	if len(req.Fields) > maxTemplateFields {
+		return fmt.Errorf("a template can have at most %d fields", maxTemplateFields)
+	}
+
+	for i := range req.Lists {
+		l := &req.Lists[i]
+		l.Name = strings.TrimSpace(l.Name)
+		if l.Name == "" {
+			return fmt.Errorf("list %d: name is required", i+1)
+		}
+		if l.Color == "" {
+			l.Color = defaultListColor
+		}
+		if l.WIPLimit < 0 {
+			return fmt.Errorf("list %d: wip_limit cannot be negative", i+1)
+		}
+	}
+	for i := range req.Tags {
+		t := &req.Tags[i]
+		t.Name = strings.TrimSpace(t.Name)
+		if t.Name == "" {
+			return fmt.Errorf("tag %d: name is required", i+1)
+		}
+		if t.Color == "" {
+			t.Color = defaultTagColor
+		}
+	}
+	for i := range req.Fields {
+		f := &req.Fields[i]
+		f.Name = strings.TrimSpace(f.Name)
+		if f.Name == "" {
+			return fmt.Errorf("field %d: name is required", i+1)
+		}
+		if !models.FieldDefinitionType(f.Type).IsFieldTypeValid() {
+			return fmt.Errorf("field %d: type must be CONTACT or COMPANY", i+1)
+		}
+		def := fieldDefinition(*f, 0)
+		if err := fieldtype.Prepare(&def); err != nil {
+			return fmt.Errorf("field %d: %w", i+1, err)
+		}
+		f.DataType = def.DataType
+		f.Currency = def.Currency
+		f.Options = f.Options[:0]
+		for _, opt := range def.Options {
+			f.Options = append(f.Options, models.TemplateOption{Name: opt.Name, Color: opt.Color})
+		}
+	}
+	return nil
+}
+
+func fieldDefinition(field models.TemplateField, userID uint) models.FieldDefinition {
+	def := models.FieldDefinition{
+		Name:     field.Name,
+		UserID:   userID,
+		Type:     field.Type,
+		DataType: field.DataType,
+		Currency: field.Currency,
+	}
+	if def.DataType == "" {
+		def.DataType = string(models.DataTypeString)
+	}
+	for i, opt := range field.Options {
+		def.Options = append(def.Options, models.FieldOption{Name: opt.Name, Color: opt.Color, Position: i})
+	}
+	return def
+}
+
+// fromBoard fills the template from one of the user's boards.
+func (s *service) fromBoard(req *TemplateReq, user models.User) error {
+	var board models.Board
+	s.DB.Where("id = ? AND user_id = ?", req.BoardID, user.ID).First(&board)
+	if board.ID == 0 {
+		logger.Logger.Error("board not found", zap.String("board_id", strconv.Itoa(int(req.BoardID))))
+		return errors.New("board not found")
+	}
+
+	var lists []models.List
+	if err := s.DB.Where("board_id = ?", board.ID).Order("list_order ASC, id ASC").Find(&lists).Error; err != nil {
+		return err
+	}
+	for _, l := range lists {
+		req.Lists = append(req.Lists, models.TemplateList{Name: l.Name, Color: l.Color, WIPLimit: l.WIPLimit})
+	}
+
+	var tags []models.Tag
+	if err := s.DB.
+		Where("user_id = ? AND id IN (?)", user.ID,
+			s.DB.Table("card_tags").
+				Select("card_tags.tag_id").
+				Joins("JOIN cards ON cards.id = card_tags.card_id AND cards.deleted_at IS NULL").
+				Joins("JOIN lists ON lists.id = cards.list_id AND lists.deleted_at IS NULL").
+				Where("lists.board_id = ?", board.ID)).
+		Order("name ASC").
+		Find(&tags).Error; err != nil {
+		return err
+	}
+	for _, t := range tags {
+		req.Tags = append(req.Tags, models.TemplateTag{Name: t.Name, Color: t.Color})
+	}
+
+	var defs []models.FieldDefinition
+	if err := fieldtype.WithOptions(s.DB).Where("user_id = ?", user.ID).Order("type ASC, id ASC").Find(&defs).Error; err != nil {
+		return err
+	}
+	for _, d := range defs {
+		field := models.TemplateField{Name: d.Name, Type: d.Type, DataType: d.DataType, Currency: d.Currency}
+		for _, opt := range d.Options {
+			field.Options = append(field.Options, models.TemplateOption{Name: opt.Name, Color: opt.Color})
+		}
+		req.Fields = append(req.Fields, field)
+	}
+	return nil
+}
+
+func (s *service) CreateTemplate(ctx context.Context, req TemplateReq, user models.User) (*Template, error) {
+	if req.BoardID != 0 {
+		if len(req.Lists) > 0 || len(req.Tags) > 0 || len(req.Fields) > 0 {
+			return nil, errors.New("pass either board_id or lists, tags and fields, not both")
+		}
+		if err := s.fromBoard(&req, user); err != nil {
+			return nil, err
+		}
+	}
+	if err := normalize(&req); err != nil {
+		return nil, err
+	}
+
+	saved := models.PipelineTemplate{
+		UserID:      user.ID,
+		Name:        req.Name,
+		Description: req.Description,
+		Lists:       req.Lists,
+		Tags:        req.Tags,
+		Fields:      req.Fields,
+	}
+	if err := s.DB.Create(&saved).Error; err != nil {
+		logger.Logger.Error("failed to create template", zap.Error(err))
+		return nil, err
+	}
+
+	t := toTemplate(saved)
+	return &t, nil
+}
+
+func (s *service) findTemplate(id uint, user models.User) (*models.PipelineTemplate, error) {
+	var saved models.PipelineTemplate
+	s.DB.Where("id = ? AND user_id = ?", id, user.ID).First(&saved)
+	if saved.ID == 0 {
+		logger.Logger.Error("template not found", zap.String("template_id", strconv.Itoa(int(id))))
+		return nil, errors.New("template not found")
+	}
+	return &saved, nil
+}
+
+func (s *service) UpdateTemplate(ctx context.Context, req UpdateTemplateReq, user models.User) (*Template, error) {
+	saved, err := s.findTemplate(req.ID, user)
+	if err != nil {
+		return nil, err
+	}
+	if req.BoardID != 0 {
+		return nil, errors.New("board_id can only be used when creating a template")
+	}
+	if err := normalize(&req.TemplateReq); err != nil {
+		return nil, err
+	}
+
+	saved.Name = req.Name
+	saved.Description = req.Description
+	saved.Lists = req.Lists
+	saved.Tags = req.Tags
+	saved.Fields = req.Fields
+	if err := s.DB.Save(saved).Error; err != nil {
+		logger.Logger.Error("failed to update template", zap.Error(err))
+		return nil, err
+	}
+
+	t := toTemplate(*saved)
+	return &t, nil
+}
+
+func (s *service) DeleteTemplate(ctx context.Context, req TemplateIDReq, user models.User) error {
+	saved, err := s.findTemplate(req.ID, user)
+	if err != nil {
+		return err
+	}
+	return s.DB.Delete(saved).Error
+}
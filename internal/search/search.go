@@ -0,0 +1,42 @@
+package search
+
+import (
+	"context"
+
+	"github.com/Cognize-AI/client-cognize/models"
+)
+
+type EntityType string
+
+const (
+	EntityCard       EntityType = "card"
+	EntityActivity   EntityType = "activity"
+	EntityFieldValue EntityType = "field_value"
+	EntityTag        EntityType = "tag"
+)
+
+type SearchReq struct {
+	Q     string `form:"q" binding:"required"`
+	Limit int    `form:"limit"`
+}
+
+type SearchResult struct {
+	Entity   EntityType `json:"entity"`
+	EntityID uint       `json:"entity_id"`
+	CardID   uint       `json:"card_id"`
+	CardName string     `json:"card_name"`
+	ListID   uint       `json:"list_id"`
+	ListName string     `json:"list_name"`
+	Field    string     `json:"field"`
+	Snippet  string     `json:"snippet"`
+	Rank     float64    `json:"rank"`
+}
+
+type SearchResp struct {
+	Query   string         `json:"query"`
+	Results []SearchResult `json:"results"`
+}
+
+type Service interface {
+	Search(ctx context.Context, req SearchReq, user models.User) (*SearchResp, error)
+}
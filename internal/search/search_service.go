@@ -0,0 +1,143 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Cognize-AI/client-cognize/config"
+	"github.com/Cognize-AI/client-cognize/logger"
+	"github.com/Cognize-AI/client-cognize/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+
+	// headlineOptions controls the snippet returned by ts_headline.
+	headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5"
+)
+
+// cardSearchColumns are the card columns covered by a full-text index in db.SyncDB.
+var cardSearchColumns = []string{"name", "email", "company_name", "designation", "ai_summary"}
+
+type service struct {
+	timeout time.Duration
+	DB      *gorm.DB
+}
+
+func NewService() Service {
+	return &service{
+		time.Duration(20) * time.Second,
+		config.DB,
+	}
+}
+
+// tsvector builds the same expression the GIN indexes are created on, so the
+// planner can use them.
+func tsvector(column string) string {
+	return fmt.Sprintf("to_tsvector('simple', coalesce(%s, ''))", column)
+}
+
+// headline builds the snippet for column. The text is HTML-escaped before
+// ts_headline adds its <mark> tags, so the snippet is safe to render as HTML.
+func headline(column string) string {
+	escaped := fmt.Sprintf("coalesce(%s, '')", column)
+	for _, r := range [][2]string{{"&", "&amp;"}, {"<", "&lt;"}, {">", "&gt;"}, {`"`, "&quot;"}, {"''", "&#39;"}} {
+		escaped = fmt.Sprintf("replace(%s, '%s', '%s')", escaped, r[0], r[1])
+	}
+	return fmt.Sprintf("ts_headline('simple', %s, q.query, '%s')", escaped, headlineOptions)
+}
+
+func (s *service) Search(ctx context.Context, req SearchReq, user models.User) (*SearchResp, error) {
+	query := strings.TrimSpace(req.Q)
+	if query == "" {
+		return nil, errors.New("search query is empty")
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	var branches []string
+	args := []interface{}{query}
+
+	for _, column := range cardSearchColumns {
+		branches = append(branches, fmt.Sprintf(`
+			SELECT '%s' AS entity, c.id AS entity_id, c.id AS card_id, c.name AS card_name,
+			       l.id AS list_id, l.name AS list_name, '%s' AS field,
+			       %s AS snippet,
+			       ts_rank(%s, q.query) AS rank
+			FROM cards c
+			JOIN lists l ON l.id = c.list_id AND l.deleted_at IS NULL
+			CROSS JOIN q
+			WHERE c.deleted_at IS NULL AND l.user_id = ? AND %s @@ q.query`,
+			EntityCard, column, headline("c."+column), tsvector("c."+column), tsvector("c."+column)))
+		args = append(args, user.ID)
+	}
+
+	branches = append(branches, fmt.Sprintf(`
+			SELECT '%s' AS entity, a.id AS entity_id, c.id AS card_id, c.name AS card_name,
+			       l.id AS list_id, l.name AS list_name, 'content' AS field,
+			       %s AS snippet,
+			       ts_rank(%s, q.query) AS rank
+			FROM activities a
+			JOIN cards c ON c.id = a.card_id AND c.deleted_at IS NULL
+			JOIN lists l ON l.id = c.list_id AND l.deleted_at IS NULL
+			CROSS JOIN q
+			WHERE a.deleted_at IS NULL AND l.user_id = ? AND %s @@ q.query`,
+		EntityActivity, headline("a.content"), tsvector("a.content"), tsvector("a.content")))
+	args = append(args, user.ID)
+
+	branches = append(branches, fmt.Sprintf(`
+			SELECT '%s' AS entity, fv.id AS entity_id, c.id AS card_id, c.name AS card_name,
+			       l.id AS list_id, l.name AS list_name, fd.name AS field,
+			       %s AS snippet,
+			       ts_rank(%s, q.query) AS rank
+			FROM field_values fv
+			JOIN field_definitions fd ON fd.id = fv.field_id AND fd.deleted_at IS NULL
+			JOIN cards c ON c.id = fv.card_id AND c.deleted_at IS NULL
+			JOIN lists l ON l.id = c.list_id AND l.deleted_at IS NULL
+			CROSS JOIN q
+			WHERE fv.deleted_at IS NULL AND l.user_id = ? AND %s @@ q.query`,
+		EntityFieldValue, headline("fv.value"), tsvector("fv.value"), tsvector("fv.value")))
+	args = append(args, user.ID)
+
+	branches = append(branches, fmt.Sprintf(`
+			SELECT '%s' AS entity, t.id AS entity_id, c.id AS card_id, c.name AS card_name,
+			       l.id AS list_id, l.name AS list_name, 'tag' AS field,
+			       %s AS snippet,
+			       ts_rank(%s, q.query) AS rank
+			FROM tags t
+			JOIN card_tags ct ON ct.tag_id = t.id
+			JOIN cards c ON c.id = ct.card_id AND c.deleted_at IS NULL
+			JOIN lists l ON l.id = c.list_id AND l.deleted_at IS NULL
+			CROSS JOIN q
+			WHERE t.deleted_at IS NULL AND t.user_id = ? AND l.user_id = t.user_id AND %s @@ q.query`,
+		EntityTag, headline("t.name"), tsvector("t.name"), tsvector("t.name")))
+	args = append(args, user.ID)
+
+	sql := "WITH q AS (SELECT websearch_to_tsquery('simple', ?) AS query) " +
+		"SELECT * FROM (" + strings.Join(branches, "\n UNION ALL \n") + ") results " +
+		"ORDER BY rank DESC, card_id DESC LIMIT ?"
+	args = append(args, limit)
+
+	var results []SearchResult
+	if err := s.DB.Raw(sql, args...).Scan(&results).Error; err != nil {
+		logger.Logger.Error("search query failed", zap.String("q", query), zap.Error(err))
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+
+	return &SearchResp{
+		Query:   query,
+		Results: results,
+	}, nil
+}
@@ -0,0 +1,132 @@
+package stagerules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Cognize-AI/client-cognize/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MissingRequirement is a required field the card has not filled in.
+type MissingRequirement struct {
+	Field   string `json:"field"`
+	FieldID uint   `json:"field_id,omitempty"`
+}
+
+// Error is returned when a card may not enter a list because the list is
+// full or the card lacks required fields.
+type Error struct {
+	ListID    uint                 `json:"list_id"`
+	ListName  string               `json:"list_name"`
+	WIPLimit  int                  `json:"wip_limit,omitempty"`
+	CardCount int64                `json:"card_count,omitempty"`
+	Missing   []MissingRequirement `json:"missing,omitempty"`
+}
+
+// Reasons describes each broken rule.
+func (e *Error) Reasons() []string {
+	var reasons []string
+	if e.WIPLimit > 0 {
+		reasons = append(reasons, fmt.Sprintf("list is at its limit of %d cards", e.WIPLimit))
+	}
+	for _, m := range e.Missing {
+		reasons = append(reasons, "missing "+m.Field)
+	}
+	return reasons
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("card cannot enter %s: %s", e.ListName, strings.Join(e.Reasons(), ", "))
+}
+
+// Lock reloads list inside tx and holds its row until tx ends, so cards
+// entering the list concurrently are checked one after another.
+func Lock(tx *gorm.DB, list *models.List) error {
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", list.ID).First(list).Error
+}
+
+// Check reports whether card may enter list. The card itself is not counted
+// against the WIP limit. values holds custom field values about to be written
+// with the card, by field id; they take the place of the stored ones, and are
+// the only ones a card without an ID has. Archived fields are skipped, since
+// no card can fill them in.
+func Check(db *gorm.DB, list models.List, card models.Card, values map[uint]string) error {
+	return CheckBehind(db, list, card, values, 0)
+}
+
+// CheckBehind is Check for a card that enters the list behind ahead other new
+// cards that are not stored yet, such as earlier rows of an import dry run.
+func CheckBehind(db *gorm.DB, list models.List, card models.Card, values map[uint]string, ahead int) error {
+	ruleErr := &Error{ListID: list.ID, ListName: list.Name}
+
+	if list.WIPLimit > 0 {
+		var count int64
+		if err := db.Model(&models.Card{}).
+			Where("list_id = ? AND id <> ?", list.ID, card.ID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		count += int64(ahead)
+		if count >= int64(list.WIPLimit) {
+			ruleErr.WIPLimit = list.WIPLimit
+			ruleErr.CardCount = count
+		}
+	}
+
+	var requirements []models.ListRequirement
+	if err := db.Where("list_id = ?", list.ID).Order("id ASC").Find(&requirements).Error; err != nil {
+		return err
+	}
+
+	var fieldIDs []uint
+	for _, r := range requirements {
+		if r.FieldID != 0 {
+			fieldIDs = append(fieldIDs, r.FieldID)
+		}
+	}
+	filled := map[uint]bool{}
+	names := map[uint]string{}
+	if len(fieldIDs) > 0 {
+		var defs []models.FieldDefinition
+		if err := db.Where("id IN ? AND NOT archived", fieldIDs).Find(&defs).Error; err != nil {
+			return err
+		}
+		for _, d := range defs {
+			names[d.ID] = d.Name
+		}
+		if card.ID != 0 {
+			var filledIDs []uint
+			if err := db.Model(&models.FieldValue{}).
+				Where("card_id = ? AND field_id IN ? AND TRIM(value) <> ''", card.ID, fieldIDs).
+				Pluck("field_id", &filledIDs).Error; err != nil {
+				return err
+			}
+			for _, id := range filledIDs {
+				filled[id] = true
+			}
+		}
+		for id, value := range values {
+			filled[id] = strings.TrimSpace(value) != ""
+		}
+	}
+
+	for _, r := range requirements {
+		if r.FieldID != 0 {
+			name, ok := names[r.FieldID]
+			if ok && !filled[r.FieldID] {
+				ruleErr.Missing = append(ruleErr.Missing, MissingRequirement{Field: name, FieldID: r.FieldID})
+			}
+			continue
+		}
+		if get, ok := models.CardFields[r.Field]; ok && strings.TrimSpace(*get(&card)) == "" {
+			ruleErr.Missing = append(ruleErr.Missing, MissingRequirement{Field: r.Field})
+		}
+	}
+
+	if ruleErr.WIPLimit == 0 && len(ruleErr.Missing) == 0 {
+		return nil
+	}
+	return ruleErr
+}
@@ -2,6 +2,7 @@ package tag
 
 import (
 	"context"
+	"time"
 
 	"github.com/Cognize-AI/client-cognize/models"
 )
@@ -26,23 +27,77 @@ type RespTag struct {
 	Color string `json:"color"`
 }
 
+// TagListUsage counts the cards with a tag in one list.
+type TagListUsage struct {
+	ListID    uint   `json:"list_id"`
+	ListName  string `json:"list_name"`
+	BoardID   uint   `json:"board_id"`
+	CardCount int64  `json:"card_count"`
+}
+
+type TagUsage struct {
+	ID        uint           `json:"id"`
+	Name      string         `json:"name"`
+	Color     string         `json:"color"`
+	CardCount int64          `json:"card_count"`
+	Lists     []TagListUsage `json:"lists"`
+}
+
 type GetAllTagsResp struct {
-	Tags []RespTag `json:"tags"`
+	Tags []TagUsage `json:"tags"`
 }
 
 type DeleteTagReq struct {
 	TagID uint `uri:"id"`
 }
 
+type TrashTag struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type GetTrashResp struct {
+	Tags []TrashTag `json:"tags"`
+}
+
+type RestoreTagReq struct {
+	TagID uint `uri:"id"`
+}
+
 type EditTagReq struct {
-	TagID uint   `json:"id"`
+	TagID uint `json:"id"`
+	// Name and Color are left unchanged when empty.
 	Name  string `json:"name"`
+	Color string `json:"color"`
 }
 
 type EditTagResp struct {
 	ID uint `json:"id"`
 }
 
+type BulkEditTagsReq struct {
+	Tags []EditTagReq `json:"tags" binding:"required"`
+}
+
+type BulkEditTagsResp struct {
+	Updated int `json:"updated"`
+}
+
+type MergeTagsReq struct {
+	TargetID  uint   `json:"target_id" binding:"required"`
+	SourceIDs []uint `json:"source_ids" binding:"required"`
+}
+
+type MergeTagsResp struct {
+	TargetID uint `json:"target_id"`
+	// MergedTagIDs are the source tags, now deleted.
+	MergedTagIDs []uint `json:"merged_tag_ids"`
+	// RetaggedCards had a source tag but not the target before the merge.
+	RetaggedCards int `json:"retagged_cards"`
+}
+
 type RemoveTagReq struct {
 	TagID  uint `json:"tag_id"`
 	CardID uint `json:"card_id"`
@@ -63,6 +118,11 @@ type Service interface {
 	AddTag(ctx context.Context, req AddTagReq, user models.User) error
 	GetAllTags(ctx context.Context, user models.User) (*GetAllTagsResp, error)
 	DeleteTag(ctx context.Context, req DeleteTagReq, user models.User) error
+	GetTrash(ctx context.Context, user models.User) (*GetTrashResp, error)
+	RestoreTag(ctx context.Context, req RestoreTagReq, user models.User) error
+	DeleteTagPermanently(ctx context.Context, req DeleteTagReq, user models.User) error
 	EditTag(ctx context.Context, req EditTagReq, user models.User) (*EditTagResp, error)
+	BulkEditTags(ctx context.Context, req BulkEditTagsReq, user models.User) (*BulkEditTagsResp, error)
+	MergeTags(ctx context.Context, req MergeTagsReq, user models.User) (*MergeTagsResp, error)
 	RemoveTagAssociation(ctx context.Context, req RemoveTagReq, user models.User) error
 }
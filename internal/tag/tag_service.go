@@ -3,17 +3,24 @@ package tag
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Cognize-AI/client-cognize/config"
+	"github.com/Cognize-AI/client-cognize/internal/automation"
+	"github.com/Cognize-AI/client-cognize/internal/history"
 	"github.com/Cognize-AI/client-cognize/logger"
 	"github.com/Cognize-AI/client-cognize/models"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+const maxBulkTags = 200
+
 type service struct {
 	timeout time.Duration
 	DB      *gorm.DB
@@ -26,14 +33,38 @@ func NewService() Service {
 	}
 }
 
+// nameTaken reports whether the user has another tag, not in the trash, with
+// the same name ignoring case.
+func nameTaken(db *gorm.DB, name string, userID, exceptID uint) (bool, error) {
+	var count int64
+	err := db.Model(&models.Tag{}).
+		Where("user_id = ? AND LOWER(name) = LOWER(?) AND id <> ?", userID, name, exceptID).
+		Count(&count).Error
+	return count > 0, err
+}
+
 func (s *service) CreateTag(ctx context.Context, req CreateTagReq, user models.User) (*CreateTagResp, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	taken, err := nameTaken(s.DB, req.Name, user.ID, 0)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, fmt.Errorf("tag %q already exists", req.Name)
+	}
+
 	var tag = models.Tag{
 		Name:   req.Name,
 		Color:  req.Color,
 		UserID: user.ID,
 	}
 
-	s.DB.Create(&tag)
+	if err := s.DB.Create(&tag).Error; err != nil {
+		return nil, err
+	}
 	return &CreateTagResp{
 		tag.ID,
 	}, nil
@@ -55,37 +86,68 @@ func (s *service) AddTag(ctx context.Context, req AddTagReq, user models.User) e
 		return errors.New("tag doesnt exists")
 	}
 
-	var existingTags []models.Tag
-	if err := s.DB.Model(&card).Association("Tags").Find(&existingTags); err != nil {
-		return err
-	}
-
-	for _, t := range existingTags {
-		if t.ID == tag.ID {
+	// Only a newly inserted link is a change; a card that already has the tag
+	// gets no history entry or automation event.
+	added := false
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec("INSERT INTO card_tags (card_id, tag_id) VALUES (?, ?) ON CONFLICT DO NOTHING", card.ID, tag.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
 			return nil
 		}
-	}
-
-	err := s.DB.Model(&card).Association("Tags").Append(&tag)
-	if err != nil {
+		added = true
+		return history.Record(tx, history.Change(card.ID, user.ID, models.CardHistoryTagAdded, "tags", "", tag.Name))
+	})
+	if err != nil || !added {
 		return err
 	}
-
+	automation.Dispatch(automation.Event{Trigger: models.TriggerTagAdded, CardID: card.ID, UserID: user.ID, TagID: tag.ID})
 	return nil
 }
 
 func (s *service) GetAllTags(ctx context.Context, user models.User) (*GetAllTagsResp, error) {
 	var tags []models.Tag
-	var respTags []RespTag
+	respTags := []TagUsage{}
 
-	s.DB.Where("user_id = ?", user.ID).Find(&tags)
+	if err := s.DB.Where("user_id = ?", user.ID).Order("name ASC, id ASC").Find(&tags).Error; err != nil {
+		return nil, err
+	}
 	logger.Logger.Info("tags found: ", zap.Int("count", len(tags)))
+
+	// A card is in one list, so the per-list counts add up to the tag's total.
+	var rows []struct {
+		TagID uint
+		TagListUsage
+	}
+	if err := s.DB.Table("card_tags").
+		Select("card_tags.tag_id, lists.id AS list_id, lists.name AS list_name, lists.board_id, COUNT(*) AS card_count").
+		Joins("JOIN cards ON cards.id = card_tags.card_id AND cards.deleted_at IS NULL").
+		Joins("JOIN lists ON lists.id = cards.list_id AND lists.deleted_at IS NULL").
+		Where("lists.user_id = ?", user.ID).
+		Group("card_tags.tag_id, lists.id, lists.name, lists.board_id").
+		Order("lists.board_id ASC, lists.list_order ASC, lists.id ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	usage := map[uint][]TagListUsage{}
+	for _, row := range rows {
+		usage[row.TagID] = append(usage[row.TagID], row.TagListUsage)
+	}
+
 	for _, tag := range tags {
-		respTags = append(respTags, RespTag{
-			tag.ID,
-			tag.Name,
-			tag.Color,
-		})
+		resp := TagUsage{
+			ID:    tag.ID,
+			Name:  tag.Name,
+			Color: tag.Color,
+			Lists: []TagListUsage{},
+		}
+		for _, l := range usage[tag.ID] {
+			resp.CardCount += l.CardCount
+			resp.Lists = append(resp.Lists, l)
+		}
+		respTags = append(respTags, resp)
 	}
 
 	return &GetAllTagsResp{respTags}, nil
@@ -103,6 +165,63 @@ func (s *service) DeleteTag(ctx context.Context, req DeleteTagReq, user models.U
 	return nil
 }
 
+// PurgeTags hard-deletes tags and their card associations.
+func PurgeTags(db *gorm.DB, tagIDs []uint) error {
+	if len(tagIDs) == 0 {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM card_tags WHERE tag_id IN ?", tagIDs).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id IN ?", tagIDs).Delete(&models.Tag{}).Error
+	})
+}
+
+func (s *service) GetTrash(ctx context.Context, user models.User) (*GetTrashResp, error) {
+	respTags := []TrashTag{}
+
+	if err := s.DB.Unscoped().
+		Model(&models.Tag{}).
+		Select("id, name, color, deleted_at").
+		Where("user_id = ? AND deleted_at IS NOT NULL", user.ID).
+		Order("deleted_at DESC").
+		Scan(&respTags).Error; err != nil {
+		return nil, err
+	}
+
+	return &GetTrashResp{respTags}, nil
+}
+
+func (s *service) RestoreTag(ctx context.Context, req RestoreTagReq, user models.User) error {
+	var tag models.Tag
+	s.DB.Unscoped().Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", req.TagID, user.ID).First(&tag)
+	if tag.ID == 0 {
+		logger.Logger.Error("tag not in trash", zap.String("tag_id", strconv.Itoa(int(req.TagID))))
+		return errors.New("tag not in trash")
+	}
+	taken, err := nameTaken(s.DB, tag.Name, user.ID, tag.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return fmt.Errorf("tag %q already exists, rename or merge it first", tag.Name)
+	}
+
+	return s.DB.Unscoped().Model(&tag).Update("deleted_at", nil).Error
+}
+
+func (s *service) DeleteTagPermanently(ctx context.Context, req DeleteTagReq, user models.User) error {
+	var tag models.Tag
+	s.DB.Unscoped().Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", req.TagID, user.ID).First(&tag)
+	if tag.ID == 0 {
+		logger.Logger.Error("tag not in trash", zap.String("tag_id", strconv.Itoa(int(req.TagID))))
+		return errors.New("tag not in trash")
+	}
+
+	return PurgeTags(s.DB, []uint{tag.ID})
+}
+
 func (s *service) EditTag(ctx context.Context, req EditTagReq, user models.User) (*EditTagResp, error) {
 	var tag models.Tag
 	s.DB.Where("id = ? AND user_id = ?", req.TagID, user.ID).First(&tag)
@@ -111,14 +230,294 @@ func (s *service) EditTag(ctx context.Context, req EditTagReq, user models.User)
 		return nil, errors.New("tag not exist")
 	}
 
-	tag.Name = req.Name
-	s.DB.Save(&tag)
+	if err := applyEdit(s.DB, &tag, req, user.ID); err != nil {
+		return nil, err
+	}
+	if err := s.DB.Save(&tag).Error; err != nil {
+		return nil, err
+	}
 
 	return &EditTagResp{
 		tag.ID,
 	}, nil
 }
 
+// applyEdit sets the new name and color on tag, keeping the current ones
+// when empty.
+func applyEdit(db *gorm.DB, tag *models.Tag, req EditTagReq, userID uint) error {
+	name := strings.TrimSpace(req.Name)
+	if name != "" && !strings.EqualFold(name, tag.Name) {
+		taken, err := nameTaken(db, name, userID, tag.ID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return fmt.Errorf("tag %q already exists", name)
+		}
+	}
+	if name != "" {
+		tag.Name = name
+	}
+	if req.Color != "" {
+		tag.Color = req.Color
+	}
+	return nil
+}
+
+func (s *service) BulkEditTags(ctx context.Context, req BulkEditTagsReq, user models.User) (*BulkEditTagsResp, error) {
+	if len(req.Tags) == 0 {
+		return nil, errors.New("tags are required")
+	}
+	if len(req.Tags) > maxBulkTags {
+		return nil, fmt.Errorf("at most %d tags can be edited at once", maxBulkTags)
+	}
+
+	ids := make([]uint, 0, len(req.Tags))
+	seen := map[uint]bool{}
+	for _, t := range req.Tags {
+		if seen[t.TagID] {
+			return nil, fmt.Errorf("tag %d is listed twice", t.TagID)
+		}
+		seen[t.TagID] = true
+		ids = append(ids, t.TagID)
+	}
+
+	var all []models.Tag
+	if err := s.DB.Where("user_id = ?", user.ID).Find(&all).Error; err != nil {
+		return nil, err
+	}
+	byID := map[uint]*models.Tag{}
+	for i := range all {
+		byID[all[i].ID] = &all[i]
+	}
+
+	// Names are checked against the result of the whole batch so tags can
+	// swap names.
+	for _, t := range req.Tags {
+		tag, ok := byID[t.TagID]
+		if !ok {
+			logger.Logger.Error("tag not exist", zap.String("tag_id", strconv.Itoa(int(t.TagID))))
+			return nil, fmt.Errorf("tag %d not exist", t.TagID)
+		}
+		if name := strings.TrimSpace(t.Name); name != "" {
+			tag.Name = name
+		}
+		if t.Color != "" {
+			tag.Color = t.Color
+		}
+	}
+	names := map[string]bool{}
+	for _, tag := range all {
+		key := strings.ToLower(tag.Name)
+		if names[key] {
+			return nil, fmt.Errorf("tag %q already exists", tag.Name)
+		}
+		names[key] = true
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		for _, id := range ids {
+			tag := byID[id]
+			if err := tx.Model(tag).Updates(map[string]interface{}{"name": tag.Name, "color": tag.Color}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Logger.Error("failed to edit tags", zap.Error(err))
+		return nil, err
+	}
+
+	return &BulkEditTagsResp{Updated: len(ids)}, nil
+}
+
+func (s *service) MergeTags(ctx context.Context, req MergeTagsReq, user models.User) (*MergeTagsResp, error) {
+	if len(req.SourceIDs) == 0 {
+		return nil, errors.New("source_ids are required")
+	}
+	sourceIDs := make([]uint, 0, len(req.SourceIDs))
+	seen := map[uint]bool{}
+	for _, id := range req.SourceIDs {
+		if id == req.TargetID {
+			return nil, errors.New("target tag cannot be one of the source tags")
+		}
+		if !seen[id] {
+			seen[id] = true
+			sourceIDs = append(sourceIDs, id)
+		}
+	}
+
+	var target models.Tag
+	s.DB.Where("id = ? AND user_id = ?", req.TargetID, user.ID).First(&target)
+	if target.ID == 0 {
+		logger.Logger.Error("tag not exist", zap.String("tag_id", strconv.Itoa(int(req.TargetID))))
+		return nil, errors.New("target tag not exist")
+	}
+	var sources []models.Tag
+	if err := s.DB.Where("id IN ? AND user_id = ?", sourceIDs, user.ID).Find(&sources).Error; err != nil {
+		return nil, err
+	}
+	if len(sources) != len(sourceIDs) {
+		return nil, errors.New("source tag not exist")
+	}
+	sourceNames := map[uint]string{}
+	for _, tag := range sources {
+		sourceNames[tag.ID] = tag.Name
+	}
+
+	res := &MergeTagsResp{TargetID: target.ID, MergedTagIDs: sourceIDs}
+	var retagged []uint
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var pairs []struct {
+			CardID uint
+			TagID  uint
+		}
+		if err := tx.Table("card_tags").
+			Select("card_id, tag_id").
+			Where("tag_id IN ?", sourceIDs).
+			Order("card_id ASC, tag_id ASC").
+			Scan(&pairs).Error; err != nil {
+			return err
+		}
+		var tagged []uint
+		if err := tx.Table("card_tags").Where("tag_id = ?", target.ID).Pluck("card_id", &tagged).Error; err != nil {
+			return err
+		}
+		hasTarget := map[uint]bool{}
+		for _, id := range tagged {
+			hasTarget[id] = true
+		}
+
+		if err := tx.Exec(`INSERT INTO card_tags (card_id, tag_id)
+			SELECT DISTINCT card_id, ? FROM card_tags WHERE tag_id IN ?
+			ON CONFLICT DO NOTHING`, target.ID, sourceIDs).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM card_tags WHERE tag_id IN ?", sourceIDs).Error; err != nil {
+			return err
+		}
+
+		var changes []models.CardHistory
+		for _, pair := range pairs {
+			changes = append(changes, history.Change(pair.CardID, user.ID, models.CardHistoryTagRemoved, "tags", sourceNames[pair.TagID], ""))
+			if !hasTarget[pair.CardID] {
+				hasTarget[pair.CardID] = true
+				retagged = append(retagged, pair.CardID)
+				changes = append(changes, history.Change(pair.CardID, user.ID, models.CardHistoryTagAdded, "tags", "", target.Name))
+			}
+		}
+		if err := history.Record(tx, changes...); err != nil {
+			return err
+		}
+
+		if err := retargetRules(tx, user.ID, seen, target.ID); err != nil {
+			return err
+		}
+		if err := retargetViews(tx, user.ID, seen, target.ID); err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", sourceIDs).Delete(&models.Tag{}).Error
+	})
+	if err != nil {
+		logger.Logger.Error("failed to merge tags", zap.Error(err))
+		return nil, err
+	}
+
+	res.RetaggedCards = len(retagged)
+	events := make([]automation.Event, 0, len(retagged))
+	for _, cardID := range retagged {
+		events = append(events, automation.Event{Trigger: models.TriggerTagAdded, CardID: cardID, UserID: user.ID, TagID: target.ID})
+	}
+	automation.Dispatch(events...)
+	return res, nil
+}
+
+// retargetRules points the user's automation rules at the target tag instead
+// of the merged ones.
+func retargetRules(tx *gorm.DB, userID uint, merged map[uint]bool, targetID uint) error {
+	var rules []models.AutomationRule
+	if err := tx.Where("user_id = ?", userID).Find(&rules).Error; err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		changed := false
+		if merged[rule.TriggerTagID] {
+			rule.TriggerTagID = targetID
+			changed = true
+		}
+		for i := range rule.Conditions {
+			if merged[rule.Conditions[i].TagID] {
+				rule.Conditions[i].TagID = targetID
+				changed = true
+			}
+		}
+		for i := range rule.Actions {
+			if merged[rule.Actions[i].TagID] {
+				rule.Actions[i].TagID = targetID
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if err := tx.Omit(clause.Associations).Save(&rule).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// retargetViews points the tag conditions of the user's saved views at the
+// target tag instead of the merged ones.
+func retargetViews(tx *gorm.DB, userID uint, merged map[uint]bool, targetID uint) error {
+	var views []models.SavedView
+	if err := tx.Where("user_id = ?", userID).Find(&views).Error; err != nil {
+		return err
+	}
+	for _, view := range views {
+		if !retargetFilter(&view.Filter, merged, targetID) {
+			continue
+		}
+		if err := tx.Omit(clause.Associations).Save(&view).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func retargetFilter(filter *models.CardFilter, merged map[uint]bool, targetID uint) bool {
+	changed := false
+	for i := range filter.Conditions {
+		cond := &filter.Conditions[i]
+		if len(cond.TagIDs) == 0 {
+			continue
+		}
+		ids := make([]uint, 0, len(cond.TagIDs))
+		hasTarget := false
+		for _, id := range cond.TagIDs {
+			if merged[id] {
+				id = targetID
+				changed = true
+			}
+			if id == targetID {
+				if hasTarget {
+					continue
+				}
+				hasTarget = true
+			}
+			ids = append(ids, id)
+		}
+		cond.TagIDs = ids
+	}
+	for i := range filter.Groups {
+		if retargetFilter(&filter.Groups[i], merged, targetID) {
+			changed = true
+		}
+	}
+	return changed
+}
+
 func (s *service) RemoveTagAssociation(ctx context.Context, req RemoveTagReq, user models.User) error {
 	var card models.Card
 	var tag models.Tag
@@ -148,9 +547,16 @@ func (s *service) RemoveTagAssociation(ctx context.Context, req RemoveTagReq, us
 		return errors.New("tag doesnt exists")
 	}
 
-	if err := s.DB.Model(&card).Association("Tags").Delete(&tag); err != nil {
-		return err
-	}
-
-	return nil
+	// Only a deleted link is a change; a card without the tag gets no history
+	// entry.
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec("DELETE FROM card_tags WHERE card_id = ? AND tag_id = ?", card.ID, tag.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		return history.Record(tx, history.Change(card.ID, user.ID, models.CardHistoryTagRemoved, "tags", tag.Name, ""))
+	})
 }
@@ -0,0 +1,65 @@
+package trash
+
+import (
+	"time"
+
+	"github.com/Cognize-AI/client-cognize/internal/card"
+	"github.com/Cognize-AI/client-cognize/internal/list"
+	"github.com/Cognize-AI/client-cognize/internal/tag"
+	"github.com/Cognize-AI/client-cognize/logger"
+	"github.com/Cognize-AI/client-cognize/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const purgeBatchSize = 500
+
+// StartPurge hard-deletes lists, cards and tags that have been in the trash
+// longer than retention, once at startup and then every interval.
+func StartPurge(db *gorm.DB, retention, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := Purge(db, time.Now().Add(-retention)); err != nil {
+				logger.Logger.Error("trash purge failed", zap.Error(err))
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// Purge hard-deletes everything soft-deleted before cutoff.
+func Purge(db *gorm.DB, cutoff time.Time) error {
+	steps := []struct {
+		model interface{}
+		purge func(db *gorm.DB, ids []uint) error
+	}{
+		{&models.List{}, list.PurgeLists},
+		{&models.Card{}, card.PurgeCards},
+		{&models.Tag{}, tag.PurgeTags},
+	}
+
+	for _, step := range steps {
+		for {
+			var ids []uint
+			if err := db.Unscoped().Model(step.model).
+				Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+				Limit(purgeBatchSize).
+				Pluck("id", &ids).Error; err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				break
+			}
+			if err := step.purge(db, ids); err != nil {
+				return err
+			}
+			logger.Logger.Info("purged trash", zap.Int("count", len(ids)))
+			if len(ids) < purgeBatchSize {
+				break
+			}
+		}
+	}
+	return nil
+}
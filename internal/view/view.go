@@ -0,0 +1,88 @@
+package view
+
+import (
+	"context"
+	"time"
+
+	"github.com/Cognize-AI/client-cognize/internal/tag"
+	"github.com/Cognize-AI/client-cognize/models"
+)
+
+type ViewReq struct {
+	Name   string            `json:"name" binding:"required"`
+	Filter models.CardFilter `json:"filter"`
+	Sort   models.CardSort   `json:"sort"`
+	// Columns defaults to name, designation, email, phone, list and tags.
+	Columns []models.ViewColumn `json:"columns"`
+}
+
+type UpdateViewReq struct {
+	ID uint `uri:"id" binding:"required"`
+	ViewReq
+}
+
+type ViewIDReq struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+type ViewResp struct {
+	ID        uint                `json:"id"`
+	Name      string              `json:"name"`
+	Filter    models.CardFilter   `json:"filter"`
+	Sort      models.CardSort     `json:"sort"`
+	Columns   []models.ViewColumn `json:"columns"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+type GetViewsRes struct {
+	Views []ViewResp `json:"views"`
+}
+
+type GetViewCardsReq struct {
+	ID     uint `uri:"id" binding:"required"`
+	Limit  int  `form:"limit"`
+	Offset int  `form:"offset"`
+}
+
+// QueryCardsReq runs a filter and sort like a saved view without saving it.
+type QueryCardsReq struct {
+	Filter models.CardFilter `json:"filter"`
+	Sort   models.CardSort   `json:"sort"`
+	// Columns defaults to the columns of a new view.
+	Columns []models.ViewColumn `json:"columns"`
+	Limit   int                 `json:"limit"`
+	Offset  int                 `json:"offset"`
+}
+
+// ViewCard holds the view's visible columns of one card. Fields are built-in
+// card fields by name, CustomFields custom field values by field id.
+type ViewCard struct {
+	ID           uint              `json:"id"`
+	ListID       uint              `json:"list_id"`
+	ListName     string            `json:"list_name"`
+	Fields       map[string]string `json:"fields"`
+	CustomFields map[uint]string   `json:"custom_fields"`
+	Tags         []tag.RespTag     `json:"tags"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+type GetViewCardsRes struct {
+	ViewID  uint                `json:"view_id,omitempty"`
+	Total   int64               `json:"total"`
+	Columns []models.ViewColumn `json:"columns"`
+	Cards   []ViewCard          `json:"cards"`
+	// NextOffset is passed as ?offset= for the next page, 0 when done.
+	NextOffset int `json:"next_offset"`
+}
+
+type Service interface {
+	CreateView(ctx context.Context, req ViewReq, user models.User) (*ViewResp, error)
+	GetViews(ctx context.Context, user models.User) (*GetViewsRes, error)
+	GetView(ctx context.Context, req ViewIDReq, user models.User) (*ViewResp, error)
+	UpdateView(ctx context.Context, req UpdateViewReq, user models.User) (*ViewResp, error)
+	DeleteView(ctx context.Context, req ViewIDReq, user models.User) error
+	GetViewCards(ctx context.Context, req GetViewCardsReq, user models.User) (*GetViewCardsRes, error)
+	QueryCards(ctx context.Context, req QueryCardsReq, user models.User) (*GetViewCardsRes, error)
+}
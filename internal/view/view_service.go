@@ -0,0 +1,325 @@
+package view
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Cognize-AI/client-cognize/config"
+	"github.com/Cognize-AI/client-cognize/internal/cardquery"
+	"github.com/Cognize-AI/client-cognize/internal/tag"
+	"github.com/Cognize-AI/client-cognize/logger"
+	"github.com/Cognize-AI/client-cognize/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+	maxColumns      = 50
+)
+
+// extraColumns can be shown besides models.CardFields and custom fields.
+var extraColumns = map[string]bool{
+	"list":       true,
+	"tags":       true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+var defaultColumns = []models.ViewColumn{
+	{Field: "name"},
+	{Field: "designation"},
+	{Field: "email"},
+	{Field: "phone"},
+	{Field: "list"},
+	{Field: "tags"},
+}
+
+type service struct {
+	timeout time.Duration
+	DB      *gorm.DB
+}
+
+func NewService() Service {
+	return &service{
+		time.Duration(20) * time.Second,
+		config.DB,
+	}
+}
+
+func (s *service) validateView(req ViewReq, user models.User) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return errors.New("name is required")
+	}
+	return s.validateQuery(req.Filter, req.Sort, req.Columns, user)
+}
+
+func (s *service) validateQuery(filter models.CardFilter, sort models.CardSort, columns []models.ViewColumn, user models.User) error {
+	if err := cardquery.Validate(s.DB, filter, sort, user.ID); err != nil {
+		return err
+	}
+	if len(columns) > maxColumns {
+		return fmt.Errorf("a view can show at most %d columns", maxColumns)
+	}
+
+	fieldIDs := map[uint]bool{}
+	for _, col := range columns {
+		if (col.Field == "") == (col.FieldID == 0) {
+			return errors.New("a column needs exactly one of field or field_id")
+		}
+		if col.FieldID != 0 {
+			fieldIDs[col.FieldID] = true
+			continue
+		}
+		if _, ok := models.CardFields[col.Field]; !ok && !extraColumns[col.Field] {
+			return fmt.Errorf("unknown column %q", col.Field)
+		}
+	}
+	if len(fieldIDs) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(fieldIDs))
+	for id := range fieldIDs {
+		ids = append(ids, id)
+	}
+	var count int64
+	if err := s.DB.Model(&models.FieldDefinition{}).
+		Where("id IN ? AND user_id = ?", ids, user.ID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count != int64(len(ids)) {
+		return errors.New("field not found")
+	}
+	return nil
+}
+
+func applyViewReq(view *models.SavedView, req ViewReq) {
+	view.Name = strings.TrimSpace(req.Name)
+	view.Filter = req.Filter
+	view.Sort = req.Sort
+	view.Columns = req.Columns
+	if len(view.Columns) == 0 {
+		view.Columns = defaultColumns
+	}
+}
+
+func toViewResp(view models.SavedView) *ViewResp {
+	return &ViewResp{
+		ID:        view.ID,
+		Name:      view.Name,
+		Filter:    view.Filter,
+		Sort:      view.Sort,
+		Columns:   view.Columns,
+		CreatedAt: view.CreatedAt,
+		UpdatedAt: view.UpdatedAt,
+	}
+}
+
+func (s *service) findView(id uint, user models.User) (*models.SavedView, error) {
+	var view models.SavedView
+	s.DB.Where("id = ? AND user_id = ?", id, user.ID).First(&view)
+	if view.ID == 0 {
+		logger.Logger.Error("view not found", zap.String("view_id", strconv.Itoa(int(id))))
+		return nil, errors.New("view not found")
+	}
+	return &view, nil
+}
+
+func (s *service) CreateView(ctx context.Context, req ViewReq, user models.User) (*ViewResp, error) {
+	if err := s.validateView(req, user); err != nil {
+		return nil, err
+	}
+
+	view := models.SavedView{UserID: user.ID}
+	applyViewReq(&view, req)
+	if err := s.DB.Create(&view).Error; err != nil {
+		logger.Logger.Error("failed to create view", zap.Error(err))
+		return nil, err
+	}
+
+	return toViewResp(view), nil
+}
+
+func (s *service) GetViews(ctx context.Context, user models.User) (*GetViewsRes, error) {
+	var views []models.SavedView
+	if err := s.DB.Where("user_id = ?", user.ID).Order("name ASC, id ASC").Find(&views).Error; err != nil {
+		return nil, err
+	}
+
+	res := &GetViewsRes{Views: []ViewResp{}}
+	for _, view := range views {
+		res.Views = append(res.Views, *toViewResp(view))
+	}
+	return res, nil
+}
+
+func (s *service) GetView(ctx context.Context, req ViewIDReq, user models.User) (*ViewResp, error) {
+	view, err := s.findView(req.ID, user)
+	if err != nil {
+		return nil, err
+	}
+	return toViewResp(*view), nil
+}
+
+func (s *service) UpdateView(ctx context.Context, req UpdateViewReq, user models.User) (*ViewResp, error) {
+	view, err := s.findView(req.ID, user)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateView(req.ViewReq, user); err != nil {
+		return nil, err
+	}
+
+	applyViewReq(view, req.ViewReq)
+	if err := s.DB.Save(view).Error; err != nil {
+		logger.Logger.Error("failed to update view", zap.Error(err))
+		return nil, err
+	}
+
+	return toViewResp(*view), nil
+}
+
+func (s *service) DeleteView(ctx context.Context, req ViewIDReq, user models.User) error {
+	view, err := s.findView(req.ID, user)
+	if err != nil {
+		return err
+	}
+	return s.DB.Delete(view).Error
+}
+
+func (s *service) GetViewCards(ctx context.Context, req GetViewCardsReq, user models.User) (*GetViewCardsRes, error) {
+	view, err := s.findView(req.ID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.queryCards(view.Filter, view.Sort, view.Columns, req.Limit, req.Offset, user)
+	if err != nil {
+		return nil, err
+	}
+	res.ViewID = view.ID
+	return res, nil
+}
+
+func (s *service) QueryCards(ctx context.Context, req QueryCardsReq, user models.User) (*GetViewCardsRes, error) {
+	if len(req.Columns) == 0 {
+		req.Columns = defaultColumns
+	}
+	if err := s.validateQuery(req.Filter, req.Sort, req.Columns, user); err != nil {
+		return nil, err
+	}
+	return s.queryCards(req.Filter, req.Sort, req.Columns, req.Limit, req.Offset, user)
+}
+
+// queryCards returns one page of the user's cards matching filter, in sort
+// order, with the given columns.
+func (s *service) queryCards(filter models.CardFilter, sort models.CardSort, columns []models.ViewColumn, limit, offset int, user models.User) (*GetViewCardsRes, error) {
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	if offset < 0 {
+		return nil, errors.New("offset must not be negative")
+	}
+
+	fields, err := cardquery.LoadFields(s.DB, filter, sort, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	query := s.DB.Model(&models.Card{}).
+		Joins("JOIN lists ON lists.id = cards.list_id AND lists.deleted_at IS NULL").
+		Where("lists.user_id = ?", user.ID)
+	query, err = cardquery.Where(query, filter, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &GetViewCardsRes{Columns: columns, Cards: []ViewCard{}}
+	if err := query.Session(&gorm.Session{}).Count(&res.Total).Error; err != nil {
+		return nil, err
+	}
+
+	query, err = cardquery.Order(query, sort, fields)
+	if err != nil {
+		return nil, err
+	}
+	var ids []uint
+	if err := query.Offset(offset).Limit(limit+1).Pluck("cards.id", &ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) > limit {
+		ids = ids[:limit]
+		res.NextOffset = offset + limit
+	}
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	var cards []models.Card
+	if err := s.DB.Preload("List").Preload("Tags").Where("id IN ?", ids).Find(&cards).Error; err != nil {
+		return nil, err
+	}
+	byID := map[uint]models.Card{}
+	for _, _card := range cards {
+		byID[_card.ID] = _card
+	}
+
+	var fieldIDs []uint
+	for _, col := range columns {
+		if col.FieldID != 0 {
+			fieldIDs = append(fieldIDs, col.FieldID)
+		}
+	}
+	values := map[uint]map[uint]string{}
+	if len(fieldIDs) > 0 {
+		var rows []models.FieldValue
+		if err := s.DB.Where("card_id IN ? AND field_id IN ?", ids, fieldIDs).Order("id ASC").Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			if values[row.CardID] == nil {
+				values[row.CardID] = map[uint]string{}
+			}
+			values[row.CardID][row.FieldID] = row.Value
+		}
+	}
+
+	for _, id := range ids {
+		_card, ok := byID[id]
+		if !ok {
+			continue
+		}
+		item := ViewCard{
+			ID:           _card.ID,
+			ListID:       _card.ListID,
+			ListName:     _card.List.Name,
+			Fields:       map[string]string{},
+			CustomFields: map[uint]string{},
+			Tags:         []tag.RespTag{},
+			CreatedAt:    _card.CreatedAt,
+			UpdatedAt:    _card.UpdatedAt,
+		}
+		for _, col := range columns {
+			if col.FieldID != 0 {
+				item.CustomFields[col.FieldID] = values[_card.ID][col.FieldID]
+			} else if get, ok := models.CardFields[col.Field]; ok {
+				item.Fields[col.Field] = *get(&_card)
+			}
+		}
+		for _, _tag := range _card.Tags {
+			item.Tags = append(item.Tags, tag.RespTag{ID: _tag.ID, Name: _tag.Name, Color: _tag.Color})
+		}
+		res.Cards = append(res.Cards, item)
+	}
+
+	return res, nil
+}
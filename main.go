@@ -7,13 +7,23 @@ import (
 	"github.com/Cognize-AI/client-cognize/config"
 	"github.com/Cognize-AI/client-cognize/db"
 	"github.com/Cognize-AI/client-cognize/internal/activity"
+	"github.com/Cognize-AI/client-cognize/internal/analytics"
+	"github.com/Cognize-AI/client-cognize/internal/automation"
+	"github.com/Cognize-AI/client-cognize/internal/board"
 	"github.com/Cognize-AI/client-cognize/internal/card"
+	"github.com/Cognize-AI/client-cognize/internal/csvimport"
+	"github.com/Cognize-AI/client-cognize/internal/export"
 	"github.com/Cognize-AI/client-cognize/internal/field"
+	"github.com/Cognize-AI/client-cognize/internal/history"
 	"github.com/Cognize-AI/client-cognize/internal/keys"
 	"github.com/Cognize-AI/client-cognize/internal/list"
 	"github.com/Cognize-AI/client-cognize/internal/oauth"
+	"github.com/Cognize-AI/client-cognize/internal/pipeline"
+	"github.com/Cognize-AI/client-cognize/internal/search"
 	"github.com/Cognize-AI/client-cognize/internal/tag"
+	"github.com/Cognize-AI/client-cognize/internal/trash"
 	"github.com/Cognize-AI/client-cognize/internal/user"
+	"github.com/Cognize-AI/client-cognize/internal/view"
 	"github.com/Cognize-AI/client-cognize/logger"
 	"github.com/Cognize-AI/client-cognize/router"
 	"go.uber.org/zap"
@@ -52,6 +62,9 @@ func main() {
 		}
 	}()
 
+	trash.StartPurge(config.DB, Config.TrashRetention(), time.Hour)
+	automation.StartScheduler(config.DB, time.Hour)
+
 	userSvc := user.NewService()
 	oauthSvc := oauth.NewService()
 	listSvc := list.NewService()
@@ -60,6 +73,15 @@ func main() {
 	keySvc := keys.NewService()
 	fieldSvc := field.NewService()
 	activitySvc := activity.NewService()
+	searchSvc := search.NewService()
+	csvImportSvc := csvimport.NewService()
+	exportSvc := export.NewService()
+	historySvc := history.NewService()
+	boardSvc := board.NewService()
+	analyticsSvc := analytics.NewService()
+	automationSvc := automation.NewService()
+	viewSvc := view.NewService()
+	pipelineSvc := pipeline.NewService()
 
 	userHandler := user.NewHandler(userSvc)
 	oauthHandler := oauth.NewHandler(oauthSvc)
@@ -69,6 +91,15 @@ func main() {
 	keyHandler := keys.NewHandler(keySvc)
 	fieldHandler := field.NewHandler(fieldSvc)
 	activityHandler := activity.NewHandler(activitySvc)
+	searchHandler := search.NewHandler(searchSvc)
+	csvImportHandler := csvimport.NewHandler(csvImportSvc)
+	exportHandler := export.NewHandler(exportSvc)
+	historyHandler := history.NewHandler(historySvc)
+	boardHandler := board.NewHandler(boardSvc)
+	analyticsHandler := analytics.NewHandler(analyticsSvc)
+	automationHandler := automation.NewHandler(automationSvc)
+	viewHandler := view.NewHandler(viewSvc)
+	pipelineHandler := pipeline.NewHandler(pipelineSvc)
 
 	router.InitRouter(
 		userHandler,
@@ -79,6 +110,15 @@ func main() {
 		keyHandler,
 		fieldHandler,
 		activityHandler,
+		searchHandler,
+		csvImportHandler,
+		exportHandler,
+		historyHandler,
+		boardHandler,
+		analyticsHandler,
+		automationHandler,
+		viewHandler,
+		pipelineHandler,
 	)
 	log.Fatal(router.Start("0.0.0.0:" + Config.PORT))
 }
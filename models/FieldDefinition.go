@@ -13,13 +13,74 @@ func (t FieldDefinitionType) IsFieldTypeValid() bool {
 	return t == CardTypeContact || t == CardTypeCompany
 }
 
+// FieldDataType is the type of a custom field's values. Values are stored as
+// normalized text, see internal/fieldtype.
+type FieldDataType string
+
+const (
+	DataTypeString      FieldDataType = "string"
+	DataTypeNumber      FieldDataType = "number"
+	DataTypeCurrency    FieldDataType = "currency"
+	DataTypeDate        FieldDataType = "date"
+	DataTypeDateTime    FieldDataType = "datetime"
+	DataTypeBoolean     FieldDataType = "boolean"
+	DataTypeEmail       FieldDataType = "email"
+	DataTypeURL         FieldDataType = "url"
+	DataTypePhone       FieldDataType = "phone"
+	DataTypeSelect      FieldDataType = "select"
+	DataTypeMultiSelect FieldDataType = "multi_select"
+)
+
+func (t FieldDataType) IsValid() bool {
+	switch t {
+	case DataTypeString, DataTypeNumber, DataTypeCurrency, DataTypeDate, DataTypeDateTime, DataTypeBoolean,
+		DataTypeEmail, DataTypeURL, DataTypePhone, DataTypeSelect, DataTypeMultiSelect:
+		return true
+	}
+	return false
+}
+
+// HasOptions reports whether values must be picked from the field's options.
+func (t FieldDataType) HasOptions() bool {
+	return t == DataTypeSelect || t == DataTypeMultiSelect
+}
+
 type FieldDefinition struct {
 	gorm.Model
 	Name     string
 	DataType string `gorm:"default:'string'"`
+	// Currency is the ISO 4217 code of currency fields.
+	Currency string `gorm:"type:varchar(3)"`
 	UserID   uint
 	Type     string `gorm:"type:varchar(20)"`
+	// FieldOrder orders the user's fields of a type on cards. Ties keep
+	// creation order.
+	FieldOrder float64
+	// Archived fields are hidden from cards but keep their values.
+	Archived bool `gorm:"default:false"`
+	// Required fields must have a value on every card written through the
+	// card API. DefaultValue, stored normalized, fills empty fields.
+	Required     bool `gorm:"default:false"`
+	DefaultValue string
+
+	User        User          `gorm:"foreignKey:UserID;references:ID"`
+	FieldValues []FieldValue  `gorm:"foreignKey:FieldID;references:ID"`
+	Options     []FieldOption `gorm:"foreignKey:FieldID;references:ID"`
+}
 
-	User        User         `gorm:"foreignKey:UserID;references:ID"`
-	FieldValues []FieldValue `gorm:"foreignKey:FieldID;references:ID"`
+// BeforeCreate puts a new field after the user's other fields of its type.
+func (d *FieldDefinition) BeforeCreate(tx *gorm.DB) error {
+	if d.FieldOrder != 0 {
+		return nil
+	}
+	var maxOrder float64
+	if err := tx.Session(&gorm.Session{NewDB: true}).
+		Model(&FieldDefinition{}).
+		Where("user_id = ? AND type = ?", d.UserID, d.Type).
+		Select("COALESCE(MAX(field_order), 0)").
+		Scan(&maxOrder).Error; err != nil {
+		return err
+	}
+	d.FieldOrder = maxOrder + 1
+	return nil
 }
@@ -0,0 +1,20 @@
+package models
+
+import "gorm.io/gorm"
+
+// FieldOption is one choice of a select or multi_select field.
+type FieldOption struct {
+	gorm.Model
+	FieldID  uint `gorm:"index"`
+	Name     string
+	Color    string
+	Position int
+}
+
+// FieldValueOption links a select or multi_select value to each option it
+// picks, so that cards can be found by option without parsing values.
+type FieldValueOption struct {
+	FieldValueID uint `gorm:"primaryKey;autoIncrement:false"`
+	OptionID     uint `gorm:"primaryKey;autoIncrement:false;index:idx_field_value_options_field_option,priority:2"`
+	FieldID      uint `gorm:"index:idx_field_value_options_field_option,priority:1"`
+}
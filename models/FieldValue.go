@@ -4,8 +4,8 @@ import "gorm.io/gorm"
 
 type FieldValue struct {
 	gorm.Model
-	CardID  uint
-	FieldID uint
+	CardID  uint `gorm:"index;index:idx_field_values_field_card,priority:2"`
+	FieldID uint `gorm:"index:idx_field_values_field_card,priority:1"`
 	Value   string
 
 	Card            Card            `gorm:"foreignKey:CardID;references:ID"`
@@ -0,0 +1,85 @@
+package models
+
+import "gorm.io/gorm"
+
+type AutomationTrigger string
+
+const (
+	TriggerCardCreated  AutomationTrigger = "CARD_CREATED"
+	TriggerCardMoved    AutomationTrigger = "CARD_MOVED"
+	TriggerTagAdded     AutomationTrigger = "TAG_ADDED"
+	TriggerFieldChanged AutomationTrigger = "FIELD_CHANGED"
+	TriggerNoActivity   AutomationTrigger = "NO_ACTIVITY"
+)
+
+type AutomationActionType string
+
+const (
+	ActionAddTag         AutomationActionType = "ADD_TAG"
+	ActionRemoveTag      AutomationActionType = "REMOVE_TAG"
+	ActionMoveToList     AutomationActionType = "MOVE_TO_LIST"
+	ActionCreateActivity AutomationActionType = "CREATE_ACTIVITY"
+	ActionSetField       AutomationActionType = "SET_FIELD"
+	ActionWebhook        AutomationActionType = "WEBHOOK"
+)
+
+type AutomationRunStatus string
+
+const (
+	AutomationRunSuccess AutomationRunStatus = "SUCCESS"
+	AutomationRunFailed  AutomationRunStatus = "FAILED"
+	// AutomationRunBlocked marks a run stopped by loop protection.
+	AutomationRunBlocked AutomationRunStatus = "BLOCKED"
+)
+
+// AutomationCondition tests a card. TagID makes it a tag condition, FieldID a
+// custom field condition; otherwise Field names a built-in card field.
+type AutomationCondition struct {
+	Field    string `json:"field,omitempty"`
+	FieldID  uint   `json:"field_id,omitempty"`
+	TagID    uint   `json:"tag_id,omitempty"`
+	Operator string `json:"operator"`
+	Value    string `json:"value,omitempty"`
+}
+
+// AutomationAction changes the card. SET_FIELD writes either a built-in card
+// field (Field) or a custom field (FieldID).
+type AutomationAction struct {
+	Type    string `json:"type"`
+	TagID   uint   `json:"tag_id,omitempty"`
+	ListID  uint   `json:"list_id,omitempty"`
+	Field   string `json:"field,omitempty"`
+	FieldID uint   `json:"field_id,omitempty"`
+	Value   string `json:"value,omitempty"`
+	URL     string `json:"url,omitempty"`
+}
+
+// AutomationRule runs its actions on a card when the trigger fires and every
+// condition matches. The trigger filters (list, tag, field) are optional and
+// 0 matches any.
+type AutomationRule struct {
+	gorm.Model
+	UserID         uint `gorm:"index"`
+	Name           string
+	Enabled        bool
+	Trigger        string `gorm:"type:varchar(30);index"`
+	TriggerListID  uint
+	TriggerTagID   uint
+	TriggerFieldID uint
+	InactiveDays   int
+	Conditions     []AutomationCondition `gorm:"type:text;serializer:json"`
+	Actions        []AutomationAction    `gorm:"type:text;serializer:json"`
+
+	User User `gorm:"foreignKey:UserID;references:ID"`
+}
+
+type AutomationRun struct {
+	gorm.Model
+	RuleID  uint   `gorm:"index"`
+	CardID  uint   `gorm:"index"`
+	Trigger string `gorm:"type:varchar(30)"`
+	Status  string `gorm:"type:varchar(20)"`
+	Error   string `gorm:"type:text"`
+
+	Rule AutomationRule `gorm:"foreignKey:RuleID;references:ID"`
+}
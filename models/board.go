@@ -0,0 +1,16 @@
+package models
+
+import "gorm.io/gorm"
+
+// DefaultBoardName is used for the board every user starts with.
+const DefaultBoardName = "Main Board"
+
+type Board struct {
+	gorm.Model
+	Name      string
+	UserID    uint `gorm:"index"`
+	IsDefault bool
+
+	User  User   `gorm:"foreignKey:UserID;references:ID"`
+	Lists []List `gorm:"foreignKey:BoardID;references:ID"`
+}
@@ -23,3 +23,21 @@ type Card struct {
 	List List  `gorm:"foreignKey:ListID;references:ID"`
 	Tags []Tag `gorm:"many2many:card_tags;"`
 }
+
+// CardFields are the free-text card columns that can be read or written by
+// name, keyed by their JSON name.
+var CardFields = map[string]func(c *Card) *string{
+	"name":             func(c *Card) *string { return &c.Name },
+	"designation":      func(c *Card) *string { return &c.Designation },
+	"email":            func(c *Card) *string { return &c.Email },
+	"phone":            func(c *Card) *string { return &c.Phone },
+	"image_url":        func(c *Card) *string { return &c.ImageURL },
+	"location":         func(c *Card) *string { return &c.Location },
+	"company_name":     func(c *Card) *string { return &c.CompanyName },
+	"company_role":     func(c *Card) *string { return &c.CompanyRole },
+	"company_location": func(c *Card) *string { return &c.CompanyLocation },
+	"company_phone":    func(c *Card) *string { return &c.CompanyPhone },
+	"company_email":    func(c *Card) *string { return &c.CompanyEmail },
+	"profile_url":      func(c *Card) *string { return &c.ProfileUrl },
+	"ai_summary":       func(c *Card) *string { return &c.AISummary },
+}
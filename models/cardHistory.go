@@ -0,0 +1,29 @@
+package models
+
+import "gorm.io/gorm"
+
+type CardHistoryKind string
+
+const (
+	CardHistoryField       CardHistoryKind = "FIELD"
+	CardHistoryCustomField CardHistoryKind = "CUSTOM_FIELD"
+	CardHistoryList        CardHistoryKind = "LIST"
+	CardHistoryTagAdded    CardHistoryKind = "TAG_ADDED"
+	CardHistoryTagRemoved  CardHistoryKind = "TAG_REMOVED"
+)
+
+// CardHistory is one recorded change to a card. Values are snapshots taken at
+// the time of the change: field, list and tag names are stored as they were.
+type CardHistory struct {
+	gorm.Model
+	CardID   uint   `gorm:"index"`
+	ActorID  uint   `gorm:"index"`
+	Kind     string `gorm:"type:varchar(20)"`
+	Field    string
+	FieldID  uint
+	OldValue string `gorm:"type:text"`
+	NewValue string `gorm:"type:text"`
+
+	Card  Card `gorm:"foreignKey:CardID;references:ID"`
+	Actor User `gorm:"foreignKey:ActorID;references:ID"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "gorm.io/gorm"
+
+type ImportStatus string
+
+const (
+	ImportStatusUploaded  ImportStatus = "UPLOADED"
+	ImportStatusCompleted ImportStatus = "COMPLETED"
+)
+
+type Import struct {
+	gorm.Model
+	FileName string
+	Content  string `gorm:"type:text"`
+	Status   string `gorm:"type:varchar(20)"`
+	UserID   uint   `gorm:"index"`
+
+	User User `gorm:"foreignKey:UserID;references:ID"`
+}
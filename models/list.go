@@ -7,8 +7,13 @@ type List struct {
 	Name      string
 	Color     string
 	UserID    uint    `gorm:"index"`
+	BoardID   uint    `gorm:"index"`
 	ListOrder float64 `gorm:"type:decimal(20,10);index"`
+	// WIPLimit caps the number of cards in the list, 0 means no limit.
+	WIPLimit int
 
-	User  User   `gorm:"foreignKey:UserID;references:ID"`
-	Cards []Card `gorm:"foreignKey:ListID;references:ID"`
+	User         User              `gorm:"foreignKey:UserID;references:ID"`
+	Board        Board             `gorm:"foreignKey:BoardID;references:ID"`
+	Cards        []Card            `gorm:"foreignKey:ListID;references:ID"`
+	Requirements []ListRequirement `gorm:"foreignKey:ListID;references:ID"`
 }
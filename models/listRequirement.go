@@ -0,0 +1,14 @@
+package models
+
+import "gorm.io/gorm"
+
+// ListRequirement is a field a card must have filled before it can enter the
+// list. Field names a built-in card field; FieldID a custom field definition.
+type ListRequirement struct {
+	gorm.Model
+	ListID  uint `gorm:"index"`
+	Field   string
+	FieldID uint
+
+	List List `gorm:"foreignKey:ListID;references:ID"`
+}
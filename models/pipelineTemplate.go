@@ -0,0 +1,42 @@
+package models
+
+import "gorm.io/gorm"
+
+type TemplateList struct {
+	Name     string `json:"name"`
+	Color    string `json:"color"`
+	WIPLimit int    `json:"wip_limit,omitempty"`
+}
+
+type TemplateTag struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type TemplateOption struct {
+	Name  string `json:"name"`
+	Color string `json:"color,omitempty"`
+}
+
+type TemplateField struct {
+	Name string `json:"name"`
+	// Type is CONTACT or COMPANY.
+	Type     string           `json:"type"`
+	DataType string           `json:"data_type,omitempty"`
+	Currency string           `json:"currency,omitempty"`
+	Options  []TemplateOption `json:"options,omitempty"`
+}
+
+// PipelineTemplate is a board layout saved by a user. Built-in templates
+// live in code.
+type PipelineTemplate struct {
+	gorm.Model
+	UserID      uint `gorm:"index"`
+	Name        string
+	Description string
+	Lists       []TemplateList  `gorm:"type:text;serializer:json"`
+	Tags        []TemplateTag   `gorm:"type:text;serializer:json"`
+	Fields      []TemplateField `gorm:"type:text;serializer:json"`
+
+	User User `gorm:"foreignKey:UserID;references:ID"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import "gorm.io/gorm"
+
+// CardCondition tests one aspect of a card. Exactly one of Field (a built-in
+// card column), FieldID (a custom field), TagIDs or ListIDs is set.
+type CardCondition struct {
+	Field    string `json:"field,omitempty"`
+	FieldID  uint   `json:"field_id,omitempty"`
+	TagIDs   []uint `json:"tag_ids,omitempty"`
+	ListIDs  []uint `json:"list_ids,omitempty"`
+	Operator string `json:"operator"`
+	Value    string `json:"value,omitempty"`
+	// Values are the operands of between and of the option operators (in,
+	// not_in, has_any, has_all, has_none) on custom fields.
+	Values []string `json:"values,omitempty"`
+}
+
+// CardFilter combines conditions and nested groups. Match is "all" (the
+// default) or "any".
+type CardFilter struct {
+	Match      string          `json:"match,omitempty"`
+	Conditions []CardCondition `json:"conditions,omitempty"`
+	Groups     []CardFilter    `json:"groups,omitempty"`
+}
+
+// CardSort orders cards by a built-in column (Field) or a custom field
+// (FieldID). Direction is "asc" or "desc".
+type CardSort struct {
+	Field     string `json:"field,omitempty"`
+	FieldID   uint   `json:"field_id,omitempty"`
+	Direction string `json:"direction,omitempty"`
+}
+
+// ViewColumn is a column shown by a saved view, either a built-in card field
+// or a custom field.
+type ViewColumn struct {
+	Field   string `json:"field,omitempty"`
+	FieldID uint   `json:"field_id,omitempty"`
+}
+
+type SavedView struct {
+	gorm.Model
+	UserID  uint `gorm:"index"`
+	Name    string
+	Filter  CardFilter   `gorm:"type:text;serializer:json"`
+	Sort    CardSort     `gorm:"type:text;serializer:json"`
+	Columns []ViewColumn `gorm:"type:text;serializer:json"`
+
+	User User `gorm:"foreignKey:UserID;references:ID"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "gorm.io/gorm"
+
+// StageTransition records a card entering a list. FromListID is 0 when the
+// card was created in ToListID. UserID is the owner of the lists.
+type StageTransition struct {
+	gorm.Model
+	CardID     uint `gorm:"index"`
+	FromListID uint
+	ToListID   uint `gorm:"index"`
+	UserID     uint `gorm:"index"`
+
+	Card Card `gorm:"foreignKey:CardID;references:ID"`
+}
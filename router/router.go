@@ -4,13 +4,22 @@ import (
 	"net/http"
 
 	"github.com/Cognize-AI/client-cognize/internal/activity"
+	"github.com/Cognize-AI/client-cognize/internal/analytics"
+	"github.com/Cognize-AI/client-cognize/internal/automation"
+	"github.com/Cognize-AI/client-cognize/internal/board"
 	"github.com/Cognize-AI/client-cognize/internal/card"
+	"github.com/Cognize-AI/client-cognize/internal/csvimport"
+	"github.com/Cognize-AI/client-cognize/internal/export"
 	"github.com/Cognize-AI/client-cognize/internal/field"
+	"github.com/Cognize-AI/client-cognize/internal/history"
 	"github.com/Cognize-AI/client-cognize/internal/keys"
 	"github.com/Cognize-AI/client-cognize/internal/list"
 	"github.com/Cognize-AI/client-cognize/internal/oauth"
+	"github.com/Cognize-AI/client-cognize/internal/pipeline"
+	"github.com/Cognize-AI/client-cognize/internal/search"
 	"github.com/Cognize-AI/client-cognize/internal/tag"
 	"github.com/Cognize-AI/client-cognize/internal/user"
+	"github.com/Cognize-AI/client-cognize/internal/view"
 	"github.com/Cognize-AI/client-cognize/logger"
 	"github.com/Cognize-AI/client-cognize/middleware"
 	"github.com/gin-contrib/cors"
@@ -28,6 +37,15 @@ func InitRouter(
 	keyHandler *keys.Handler,
 	fieldHandler *field.Handler,
 	activityHandler *activity.Handler,
+	searchHandler *search.Handler,
+	csvImportHandler *csvimport.Handler,
+	exportHandler *export.Handler,
+	historyHandler *history.Handler,
+	boardHandler *board.Handler,
+	analyticsHandler *analytics.Handler,
+	automationHandler *automation.Handler,
+	viewHandler *view.Handler,
+	pipelineHandler *pipeline.Handler,
 ) {
 	r = gin.Default()
 
@@ -57,16 +75,53 @@ func InitRouter(
 		oAuthRouter.GET("/google/callback", oauthHandler.HandleGoogleCallback)
 	}
 
+	boardRouter := r.Group("/board")
+	{
+		boardRouter.POST("/create", middleware.RequireAuth, boardHandler.CreateBoard)
+		boardRouter.GET("/all", middleware.RequireAuth, boardHandler.GetBoards)
+		boardRouter.PUT("/:id", middleware.RequireAuth, boardHandler.UpdateBoard)
+		boardRouter.DELETE("/:id", middleware.RequireAuth, boardHandler.DeleteBoard)
+	}
+
+	pipelineRouter := r.Group("/pipeline")
+	{
+		pipelineRouter.GET("/templates", middleware.RequireAuth, pipelineHandler.GetTemplates)
+		pipelineRouter.POST("/templates", middleware.RequireAuth, pipelineHandler.CreateTemplate)
+		pipelineRouter.PUT("/templates/:id", middleware.RequireAuth, pipelineHandler.UpdateTemplate)
+		pipelineRouter.DELETE("/templates/:id", middleware.RequireAuth, pipelineHandler.DeleteTemplate)
+	}
+
 	listRouter := r.Group("/list")
 	{
 		listRouter.GET("/create-default", middleware.RequireAuth, listHandler.CreateDefaultLists)
 		listRouter.GET("/all", middleware.RequireAuth, listHandler.GetLists)
+		listRouter.GET("/summary", middleware.RequireAuth, listHandler.GetBoardSummary)
+		listRouter.GET("/trash", middleware.RequireAuth, listHandler.GetTrash)
+		listRouter.POST("/:id/restore", middleware.RequireAuth, listHandler.RestoreList)
+		listRouter.DELETE("/:id/permanent", middleware.RequireAuth, listHandler.DeleteListPermanently)
+		listRouter.GET("/:id/cards", middleware.RequireAuth, listHandler.GetListCards)
+		listRouter.GET("/:id/rules", middleware.RequireAuth, listHandler.GetListRules)
+		listRouter.PUT("/:id/rules", middleware.RequireAuth, listHandler.UpdateListRules)
+		listRouter.POST("/create", middleware.RequireAuth, listHandler.CreateList)
+		listRouter.POST("/move", middleware.RequireAuth, listHandler.MoveList)
+		listRouter.PUT("/:id", middleware.RequireAuth, listHandler.UpdateList)
+		listRouter.DELETE("/:id", middleware.RequireAuth, listHandler.DeleteList)
 	}
 
 	cardRouter := r.Group("/card")
 	{
 		cardRouter.POST("/create", middleware.RequireAuth, cardHandler.CreateCard)
 		cardRouter.POST("/move", middleware.RequireAuth, cardHandler.MoveCard)
+		cardRouter.GET("/duplicates", middleware.RequireAuth, cardHandler.FindDuplicates)
+		cardRouter.GET("/trash", middleware.RequireAuth, cardHandler.GetTrash)
+		cardRouter.POST("/:id/restore", middleware.RequireAuth, cardHandler.RestoreCard)
+		cardRouter.DELETE("/:id/permanent", middleware.RequireAuth, cardHandler.DeleteCardPermanently)
+		cardRouter.POST("/merge", middleware.RequireAuth, cardHandler.MergeCards)
+		cardRouter.POST("/bulk", middleware.RequireAuth, cardHandler.BulkUpdate)
+		cardRouter.GET("/vcard", middleware.RequireAuth, cardHandler.ExportListVCard)
+		cardRouter.POST("/vcard/import", middleware.RequireAuth, cardHandler.ImportVCard)
+		cardRouter.GET("/:id/vcard", middleware.RequireAuth, cardHandler.ExportCardVCard)
+		cardRouter.GET("/:id/history", middleware.RequireAuth, historyHandler.GetCardHistory)
 		cardRouter.DELETE("/:id", middleware.RequireAuth, cardHandler.DeleteCard)
 		cardRouter.PUT("/:id", middleware.RequireAuth, cardHandler.UpdateCard)
 		cardRouter.GET("/:id", middleware.RequireAuth, cardHandler.GetCardById)
@@ -79,7 +134,12 @@ func InitRouter(
 		tagRouter.POST("/add-to-card", middleware.RequireAuth, tagHandler.AddTag)
 		tagRouter.GET("/", middleware.RequireAuth, tagHandler.GetAllTags)
 		tagRouter.DELETE("/:id", middleware.RequireAuth, tagHandler.DeleteTag)
+		tagRouter.GET("/trash", middleware.RequireAuth, tagHandler.GetTrash)
+		tagRouter.POST("/:id/restore", middleware.RequireAuth, tagHandler.RestoreTag)
+		tagRouter.DELETE("/:id/permanent", middleware.RequireAuth, tagHandler.DeleteTagPermanently)
 		tagRouter.PUT("/", middleware.RequireAuth, tagHandler.EditTag)
+		tagRouter.PUT("/bulk", middleware.RequireAuth, tagHandler.BulkEditTags)
+		tagRouter.POST("/merge", middleware.RequireAuth, tagHandler.MergeTags)
 		tagRouter.POST("/remove-from-card", middleware.RequireAuth, tagHandler.RemoveTagAssociation)
 	}
 
@@ -100,6 +160,15 @@ func InitRouter(
 		fieldRouter.POST("/field-value", middleware.RequireAuth, fieldHandler.InsertFieldVal)
 		fieldRouter.GET("/", middleware.RequireAuth, fieldHandler.GetFields)
 		fieldRouter.PUT("/", middleware.RequireAuth, fieldHandler.UpdateFieldDefinition)
+		fieldRouter.POST("/move", middleware.RequireAuth, fieldHandler.MoveField)
+		fieldRouter.DELETE("/:id", middleware.RequireAuth, fieldHandler.DeleteField)
+		fieldRouter.PUT("/:id/archive", middleware.RequireAuth, fieldHandler.ArchiveField)
+		fieldRouter.POST("/:id/convert", middleware.RequireAuth, fieldHandler.ConvertField)
+		fieldRouter.GET("/:id/options", middleware.RequireAuth, fieldHandler.GetOptions)
+		fieldRouter.POST("/:id/options", middleware.RequireAuth, fieldHandler.CreateOption)
+		fieldRouter.PUT("/:id/options/order", middleware.RequireAuth, fieldHandler.ReorderOptions)
+		fieldRouter.PUT("/:id/options/:option_id", middleware.RequireAuth, fieldHandler.UpdateOption)
+		fieldRouter.DELETE("/:id/options/:option_id", middleware.RequireAuth, fieldHandler.DeleteOption)
 	}
 
 	activityRouter := r.Group("/activity")
@@ -108,6 +177,44 @@ func InitRouter(
 		activityRouter.DELETE("/:id", middleware.RequireAuth, activityHandler.DeleteActivity)
 		activityRouter.PUT("/:id", middleware.RequireAuth, activityHandler.UpdateActivity)
 	}
+
+	r.GET("/search", middleware.RequireAuth, searchHandler.Search)
+
+	importRouter := r.Group("/import")
+	{
+		importRouter.POST("/csv", middleware.RequireAuth, csvImportHandler.UploadCSV)
+		importRouter.POST("/csv/:id/run", middleware.RequireAuth, csvImportHandler.RunImport)
+	}
+
+	exportRouter := r.Group("/export")
+	{
+		exportRouter.GET("/cards", middleware.RequireAuth, exportHandler.ExportCards)
+	}
+
+	analyticsRouter := r.Group("/analytics")
+	{
+		analyticsRouter.GET("/stages", middleware.RequireAuth, analyticsHandler.GetStageAnalytics)
+	}
+
+	automationRouter := r.Group("/automation")
+	{
+		automationRouter.POST("/rules", middleware.RequireAuth, automationHandler.CreateRule)
+		automationRouter.GET("/rules", middleware.RequireAuth, automationHandler.GetRules)
+		automationRouter.PUT("/rules/:id", middleware.RequireAuth, automationHandler.UpdateRule)
+		automationRouter.DELETE("/rules/:id", middleware.RequireAuth, automationHandler.DeleteRule)
+		automationRouter.GET("/rules/:id/runs", middleware.RequireAuth, automationHandler.GetRuns)
+	}
+
+	viewRouter := r.Group("/view")
+	{
+		viewRouter.POST("/create", middleware.RequireAuth, viewHandler.CreateView)
+		viewRouter.GET("/all", middleware.RequireAuth, viewHandler.GetViews)
+		viewRouter.POST("/query", middleware.RequireAuth, viewHandler.QueryCards)
+		viewRouter.GET("/:id/cards", middleware.RequireAuth, viewHandler.GetViewCards)
+		viewRouter.GET("/:id", middleware.RequireAuth, viewHandler.GetView)
+		viewRouter.PUT("/:id", middleware.RequireAuth, viewHandler.UpdateView)
+		viewRouter.DELETE("/:id", middleware.RequireAuth, viewHandler.DeleteView)
+	}
 }
 
 func Start(addr string) error {
@@ -0,0 +1,50 @@
+package util
+
+import (
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+const minPhoneDigits = 7
+
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// NormalizePhone keeps only digits and compares on the last ten so that
+// "+1 (555) 010-9999" and "555 010 9999" collide.
+func NormalizePhone(phone string) string {
+	var b strings.Builder
+	for _, r := range phone {
+		if unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	digits := b.String()
+	if len(digits) < minPhoneDigits {
+		return ""
+	}
+	if len(digits) > 10 {
+		digits = digits[len(digits)-10:]
+	}
+	return digits
+}
+
+// NormalizeProfileURL reduces a profile link to host and path, dropping the
+// scheme, "www.", query string and trailing slash.
+func NormalizeProfileURL(raw string) string {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	if raw == "" {
+		return ""
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	host := strings.TrimPrefix(u.Host, "www.")
+	return host + strings.TrimRight(u.Path, "/")
+}